@@ -0,0 +1,188 @@
+package draw
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gregoryv/nexus"
+)
+
+// Def is a definition written once in the defs section of an SVG and
+// referenced by its id, e.g. a filter.
+type Def interface {
+	SVGWriter
+	DefID() string
+}
+
+// HasDefs is implemented by content referring to definitions.
+type HasDefs interface {
+	Defs() []Def
+}
+
+// Filter is an SVG filter, e.g. a drop shadow, referenced with
+// filter="url(#id)".
+type Filter struct {
+	ID string
+
+	// Primitives are the filter effects, e.g. feGaussianBlur
+	// elements.
+	Primitives string
+}
+
+func (f *Filter) DefID() string { return f.ID }
+
+func (f *Filter) WriteSVG(out io.Writer) error {
+	_, err := fmt.Fprintf(out,
+		`<filter id="%s" x="-20%%" y="-20%%" width="140%%" height="140%%">%s</filter>`,
+		f.ID, f.Primitives)
+	return err
+}
+
+// URL returns the reference to the definition, e.g. for a fill
+// attribute.
+func URL(d Def) string { return "url(#" + d.DefID() + ")" }
+
+// Stop is a color at offset, 0 to 1, of a gradient.
+type Stop struct {
+	Offset float64
+	Color  string
+}
+
+// stops returns colors evenly spread from 0 to 1.
+func stops(colors []string) []Stop {
+	stops := make([]Stop, len(colors))
+	for i, c := range colors {
+		stops[i] = Stop{Color: c}
+		if len(colors) > 1 {
+			stops[i].Offset = float64(i) / float64(len(colors)-1)
+		}
+	}
+	return stops
+}
+
+func writeStops(w *nexus.Printer, stops []Stop) {
+	for _, s := range stops {
+		w.Printf(`<stop offset="%v" stop-color="%s"/>`, s.Offset, s.Color)
+	}
+}
+
+// NewLinearGradient returns a gradient from left to right with the
+// colors evenly spread.
+func NewLinearGradient(id string, colors ...string) *LinearGradient {
+	return &LinearGradient{
+		ID:    id,
+		Stops: stops(colors),
+	}
+}
+
+// LinearGradient changes color along a line, see Angle.
+type LinearGradient struct {
+	ID    string
+	Stops []Stop
+
+	// Angle in degrees, clockwise from left to right, e.g. 90 is
+	// top to bottom.
+	Angle int
+}
+
+func (g *LinearGradient) DefID() string { return g.ID }
+
+func (g *LinearGradient) WriteSVG(out io.Writer) error {
+	w, err := nexus.NewPrinter(out)
+	w.Printf(`<linearGradient id="%s"`, g.ID)
+	if g.Angle != 0 {
+		w.Printf(` gradientTransform="rotate(%v 0.5 0.5)"`, g.Angle)
+	}
+	w.Print(">")
+	writeStops(w, g.Stops)
+	w.Print("</linearGradient>")
+	return *err
+}
+
+// NewRadialGradient returns a gradient from the center outwards
+// with the colors evenly spread.
+func NewRadialGradient(id string, colors ...string) *RadialGradient {
+	return &RadialGradient{
+		ID:    id,
+		Stops: stops(colors),
+	}
+}
+
+// RadialGradient changes color from the center outwards.
+type RadialGradient struct {
+	ID    string
+	Stops []Stop
+}
+
+func (g *RadialGradient) DefID() string { return g.ID }
+
+func (g *RadialGradient) WriteSVG(out io.Writer) error {
+	w, err := nexus.NewPrinter(out)
+	w.Printf(`<radialGradient id="%s">`, g.ID)
+	writeStops(w, g.Stops)
+	w.Print("</radialGradient>")
+	return *err
+}
+
+// NewHatch returns a pattern of diagonal lines in the given color.
+func NewHatch(id, color string) *Hatch {
+	return &Hatch{
+		ID:    id,
+		Color: color,
+		Size:  6,
+		Angle: 45,
+	}
+}
+
+// Hatch is a pattern of parallel lines, e.g. for areas that are out
+// of scope.
+type Hatch struct {
+	ID    string
+	Color string
+
+	// Size is the distance between lines.
+	Size int
+
+	// Angle of the lines in degrees, zero is vertical.
+	Angle int
+}
+
+func (h *Hatch) DefID() string { return h.ID }
+
+func (h *Hatch) WriteSVG(out io.Writer) error {
+	_, err := fmt.Fprintf(out,
+		`<pattern id="%s" patternUnits="userSpaceOnUse" width="%v" height="%v" patternTransform="rotate(%v)"><line x1="0" y1="0" x2="0" y2="%v" stroke="%s" stroke-width="1"/></pattern>`,
+		h.ID, h.Size, h.Size, h.Angle, h.Size, h.Color)
+	return err
+}
+
+// Define adds definitions to the document, e.g. gradients referred
+// to by classes. Definitions with the same id are written once.
+func (s *SVG) Define(defs ...Def) {
+	s.defs = append(s.defs, defs...)
+}
+
+// Defs returns the definitions added with Define followed by those
+// of the content, each id only once.
+func (s *SVG) Defs() []Def {
+	var (
+		all  = make([]Def, 0)
+		seen = make(map[string]bool)
+	)
+	add := func(defs []Def) {
+		for _, d := range defs {
+			if seen[d.DefID()] {
+				continue
+			}
+			seen[d.DefID()] = true
+			all = append(all, d)
+		}
+	}
+	add(s.defs)
+	for _, c := range s.Content {
+		if c, ok := c.(HasDefs); ok {
+			add(c.Defs())
+		}
+	}
+	return all
+}
@@ -1,11 +1,18 @@
 package design
 
-import "github.com/gregoryv/draw/shape"
+import (
+	"io"
+
+	"github.com/gregoryv/draw"
+	"github.com/gregoryv/draw/shape"
+	"github.com/gregoryv/draw/xy"
+)
 
 func NewActivityDiagram() *ActivityDiagram {
 	return &ActivityDiagram{
-		Diagram: NewDiagram(),
-		Spacing: 40,
+		Diagram:  NewDiagram(),
+		Spacing:  40,
+		branches: make(map[shape.Shape][]shape.Shape),
 	}
 }
 
@@ -13,14 +20,32 @@ type ActivityDiagram struct {
 	*Diagram
 	last    shape.Shape
 	Spacing int
+
+	links    []*activityLink
+	bars     []*shape.Rect
+	branches map[shape.Shape][]shape.Shape // first shape of each flow
+	lanes    []*Lane
+	lane     *Lane         // current
+	nodes    []shape.Shape // all activities, used when routing
+	exit     *loopExit     // pending exit of a while loop
+	arranged bool          // activities moved into lanes
+}
+
+// activityLink keeps track of linked shapes so arrows can be
+// updated when shapes are moved, e.g. into lanes.
+type activityLink struct {
+	arrow    *shape.Arrow
+	label    *shape.Label
+	from, to shape.Shape
+	// side is 1 or -1 for links routed around the activities on
+	// the right or left side, 0 for direct links.
+	side int
 }
 
 // LinkAll places arrows between each shape, s0->s1->...->sn
 func (d *ActivityDiagram) LinkAll(s ...shape.Shape) {
 	for i, next := range s[1:] {
-		lnk := shape.NewArrowBetween(s[i], next)
-		lnk.SetClass("activity-arrow")
-		d.Place(lnk)
+		d.Link(s[i], next)
 	}
 }
 
@@ -30,35 +55,72 @@ func (d *ActivityDiagram) Link(from, to shape.Shape, txt ...string) *shape.Arrow
 	lnk := shape.NewArrowBetween(from, to)
 	lnk.SetClass("activity-arrow")
 	d.Place(lnk)
+	l := &activityLink{
+		arrow: lnk,
+		from:  from,
+		to:    to,
+	}
 	if len(txt) > 0 {
-		d.placeLabel(lnk, txt[0])
+		l.label = lnk.AddLabel(txt[0], shape.LabelMiddle)
 	}
+	d.links = append(d.links, l)
 	return lnk
 }
 
-func (d *ActivityDiagram) placeLabel(lnk *shape.Arrow, txt string) {
-	label := shape.NewLabel(txt)
-	switch lnk.Direction() {
-	case shape.DirectionRight, shape.DirectionLeft:
-		d.Place(label).Above(lnk, 20)
-		d.VAlignCenter(lnk, label)
-		shape.Move(label, -4, 0)
-	case shape.DirectionUp, shape.DirectionDown:
-		d.Place(label).RightOf(lnk, 5)
+// linkNext links the last activity, or the pending exit of a while
+// loop, to next.
+func (d *ActivityDiagram) linkNext(next shape.Shape, txt ...string) {
+	if d.exit == nil {
+		d.Link(d.last, next, txt...)
+		return
+	}
+	if len(txt) == 0 {
+		txt = []string{d.exit.txt}
 	}
+	d.linkSide(d.exit.loop, next, 1, txt...)
+	d.exit = nil
+}
+
+// linkSide links the two shapes with an arrow routed around the
+// activities between them on the given side, 1 for right and -1
+// for left.
+func (d *ActivityDiagram) linkSide(from, to shape.Shape, side int, txt ...string) *shape.Arrow {
+	last := d.last // Link places the arrow as last
+	a := d.Link(from, to, txt...)
+	d.links[len(d.links)-1].side = side
+	d.last = last
+	return a
 }
 
+// Place adds the shapes to the diagram and the current lane, if
+// any.
 func (d *ActivityDiagram) Place(next ...shape.Shape) *shape.Adjuster {
 	d.last = next[0]
+	for _, s := range next {
+		switch s.(type) {
+		case *shape.Arrow, *shape.Label:
+		default:
+			d.assign(s)
+		}
+	}
 	return d.Diagram.Place(next...)
 }
 
+// Last returns the last placed activity.
+func (d *ActivityDiagram) Last() shape.Shape { return d.last }
+
+// place adds the shape to the diagram and current lane.
+func (d *ActivityDiagram) place(s shape.Shape) *shape.Adjuster {
+	d.assign(s)
+	return d.Diagram.Place(s)
+}
+
 func (d *ActivityDiagram) Then(label string, txt ...string) *shape.Adjuster {
 	next := shape.NewState(label)
-	adj := d.Diagram.Place(next)
+	adj := d.place(next)
 	adj.Below(d.last, d.Spacing)
 	d.VAlignCenter(d.last, next)
-	d.Link(d.last, next, txt...)
+	d.linkNext(next, txt...)
 	d.last = next
 	return adj
 }
@@ -66,10 +128,10 @@ func (d *ActivityDiagram) Then(label string, txt ...string) *shape.Adjuster {
 // Decide adds a diamond below the last activity
 func (d *ActivityDiagram) Decide() *shape.Diamond {
 	next := shape.NewDecision()
-	adj := d.Diagram.Place(next)
-	adj.Below(d.last, d.Spacing+next.Height()/2)
+	adj := d.place(next)
+	adj.Below(d.last, d.Spacing)
 	d.VAlignCenter(d.last, next)
-	d.Link(d.last, next)
+	d.linkNext(next)
 	d.last = next
 	return next
 }
@@ -77,17 +139,17 @@ func (d *ActivityDiagram) Decide() *shape.Diamond {
 // Exit adds an ExitDot below the last activity
 func (d *ActivityDiagram) Exit(txt ...string) *shape.ExitDot {
 	next := shape.NewExitDot()
-	adj := d.Diagram.Place(next)
+	adj := d.place(next)
 	adj.Below(d.last, d.Spacing)
 	d.VAlignCenter(d.last, next)
-	d.Link(d.last, next, txt...)
+	d.linkNext(next, txt...)
 	d.last = next
 	return next
 }
 
 // If adds next state to the right with a label.
 func (d *ActivityDiagram) If(after shape.Shape, txt string, next shape.Shape) *shape.Adjuster {
-	adj := d.Diagram.Place(next)
+	adj := d.place(next)
 	adj.RightOf(after, d.Font.TextWidth(txt)+d.Spacing)
 	d.HAlignCenter(after, next)
 	d.Link(after, next, txt)
@@ -100,3 +162,405 @@ func (d *ActivityDiagram) Start() *shape.Adjuster {
 	d.last = start
 	return d.Place(start)
 }
+
+// Fork adds a synchronization bar below the last activity from
+// which parallel flows start. Add the flows with Branch.
+func (d *ActivityDiagram) Fork() *shape.Rect {
+	bar := d.newBar()
+	d.place(bar).Below(d.last, d.Spacing)
+	d.VAlignCenter(d.last, bar)
+	d.linkNext(bar)
+	d.last = bar
+	return bar
+}
+
+// Branch adds an activity below from, to the right of any previous
+// branches of from. From is usually a fork bar or a decision. Use
+// Then to continue the flow below the branch.
+func (d *ActivityDiagram) Branch(from shape.Shape, label string, txt ...string) *shape.Adjuster {
+	next := shape.NewState(label)
+	adj := d.place(next)
+	prev := d.branches[from]
+	if len(prev) == 0 {
+		adj.Below(from, d.Spacing)
+		d.VAlignCenter(from, next)
+	} else {
+		adj.RightOf(prev[len(prev)-1], d.Spacing)
+	}
+	d.branches[from] = append(prev, next)
+	d.Link(from, next, txt...)
+	d.last = next
+	return adj
+}
+
+// Join adds a synchronization bar below the lowest of the given
+// shapes where the parallel flows end.
+func (d *ActivityDiagram) Join(ends ...shape.Shape) *shape.Rect {
+	bar := d.newBar()
+	d.place(bar).Below(lowest(ends...), d.Spacing)
+	d.VAlignCenter(ends[0], bar)
+	for _, end := range ends {
+		d.Link(end, bar)
+	}
+	d.last = bar
+	return bar
+}
+
+// Merge adds a diamond below the lowest of the given shapes where
+// alternative flows come together.
+func (d *ActivityDiagram) Merge(ends ...shape.Shape) *shape.Diamond {
+	next := shape.NewDecision()
+	d.place(next).Below(lowest(ends...), d.Spacing)
+	d.VAlignCenter(ends[0], next)
+	for _, end := range ends {
+		d.Link(end, next)
+	}
+	d.last = next
+	return next
+}
+
+// Until adds a decision below the last activity with a back-edge to
+// first, ie. the activities from first are repeated until the
+// decision is passed. The guard is shown next to the back-edge.
+func (d *ActivityDiagram) Until(first shape.Shape, guard string) *shape.Diamond {
+	dec := d.Decide()
+	d.linkSide(dec, first, 1, guard)
+	return dec
+}
+
+// While adds a decision below the last activity starting a loop.
+// Add the body with Then and close the loop with EndWhile.
+func (d *ActivityDiagram) While() *shape.Diamond {
+	return d.Decide()
+}
+
+// EndWhile adds a back-edge from the last activity to the loop
+// decision. The next activity is placed below the body and linked
+// from the decision with the given exit label.
+func (d *ActivityDiagram) EndWhile(loop *shape.Diamond, txt string) {
+	d.linkSide(d.last, loop, -1)
+	d.exit = &loopExit{loop: loop, txt: txt}
+}
+
+type loopExit struct {
+	loop shape.Shape
+	txt  string
+}
+
+func (d *ActivityDiagram) newBar() *shape.Rect {
+	bar := shape.NewRect("")
+	bar.SetClass("bar")
+	bar.SetHeight(4)
+	bar.SetWidth(2 * d.Spacing)
+	d.bars = append(d.bars, bar)
+	return bar
+}
+
+func (d *ActivityDiagram) isBar(s shape.Shape) bool {
+	for _, bar := range d.bars {
+		if s == bar {
+			return true
+		}
+	}
+	return false
+}
+
+func lowest(s ...shape.Shape) shape.Shape {
+	var low shape.Shape
+	var max int
+	for _, s := range s {
+		_, y := s.Position()
+		if bottom := y + s.Height(); low == nil || bottom > max {
+			low, max = s, bottom
+		}
+	}
+	return low
+}
+
+// Lane adds a swimlane with the given title. Activities placed after
+// are put in the lane until another lane is selected with InLane.
+// Activities placed before the first lane are put in a leading lane
+// without title.
+func (d *ActivityDiagram) Lane(title string) *Lane {
+	if len(d.lanes) == 0 && len(d.nodes) > 0 {
+		first := &Lane{}
+		first.shapes = append(first.shapes, d.nodes...)
+		d.lanes = append(d.lanes, first)
+	}
+	l := &Lane{Title: title}
+	d.lanes = append(d.lanes, l)
+	d.lane = l
+	return l
+}
+
+// InLane selects the lane for following activities.
+func (d *ActivityDiagram) InLane(l *Lane) { d.lane = l }
+
+func (d *ActivityDiagram) assign(s shape.Shape) {
+	d.nodes = append(d.nodes, s)
+	if d.lane != nil {
+		d.lane.shapes = append(d.lane.shapes, s)
+	}
+}
+
+// Lane is a vertical partition of an activity diagram. Lanes are
+// sized to fit their activities.
+type Lane struct {
+	Title  string
+	shapes []shape.Shape
+	width  int // set when arranged
+}
+
+// WriteSVG renders the diagram as SVG to the given writer.
+func (d *ActivityDiagram) WriteSVG(w io.Writer) error {
+	d.arrangeLanes()
+	d.fitBars()
+	// direct links first so routed links can avoid their labels
+	for _, l := range d.links {
+		if l.side == 0 {
+			d.route(l)
+		}
+	}
+	for _, l := range d.links {
+		if l.side != 0 {
+			d.routeSide(l)
+		}
+	}
+	// lanes are drawn behind the activities
+	content := d.Content
+	d.Content = append(d.laneShapes(), content...)
+	defer func() { d.Content = content }()
+	return d.Diagram.WriteSVG(w)
+}
+
+// arrangeLanes moves activities of each lane next to each other,
+// only once so the diagram can be written many times.
+func (d *ActivityDiagram) arrangeLanes() {
+	if len(d.lanes) == 0 || d.arranged {
+		return
+	}
+	d.arranged = true
+	header := d.laneHeader()
+	var x int
+	for _, l := range d.lanes {
+		left, right, _ := l.bounds()
+		l.width = d.laneRect(l).Width()
+		// center the activities in the lane
+		dx := x + (l.width-(right-left))/2 - left
+		for _, s := range l.shapes {
+			shape.Move(s, dx, header)
+		}
+		x += l.width
+	}
+}
+
+// laneShapes returns the lane boxes and header lines of arranged
+// lanes.
+func (d *ActivityDiagram) laneShapes() []draw.SVGWriter {
+	header := d.laneHeader()
+	var x, bottom int
+	for _, l := range d.lanes {
+		if _, _, b := l.bounds(); b > bottom {
+			bottom = b
+		}
+	}
+	shapes := make([]draw.SVGWriter, 0, 2*len(d.lanes))
+	for _, l := range d.lanes {
+		r := d.laneRect(l)
+		r.SetX(x)
+		r.SetHeight(bottom + d.Spacing)
+		line := shape.NewLine(x, header, x+r.Width(), header)
+		line.SetClass("lane-line")
+		shapes = append(shapes, r, line)
+		x += r.Width()
+	}
+	return shapes
+}
+
+func (d *ActivityDiagram) laneHeader() int {
+	return d.Font.LineHeight + d.TextPad.Top + d.TextPad.Bottom
+}
+
+// laneRect returns a styled box of the lane fitting its title and
+// activities.
+func (d *ActivityDiagram) laneRect(l *Lane) *shape.Rect {
+	r := shape.NewRect(l.Title)
+	r.SetClass("lane")
+	d.applyStyle(r)
+	if l.width > 0 {
+		r.SetWidth(l.width)
+		return r
+	}
+	left, right, _ := l.bounds()
+	if width := right - left + 2*d.Spacing; width > r.Width() {
+		r.SetWidth(width)
+	}
+	return r
+}
+
+// bounds returns the left, right and bottom of the lane activities.
+func (l *Lane) bounds() (left, right, bottom int) {
+	left = -1
+	for _, s := range l.shapes {
+		sx, sy := s.Position()
+		if left == -1 || sx < left {
+			left = sx
+		}
+		if v := sx + s.Width(); v > right {
+			right = v
+		}
+		if v := sy + s.Height(); v > bottom {
+			bottom = v
+		}
+	}
+	return
+}
+
+// fitBars resizes synchronization bars to span the flows they
+// fork or join.
+func (d *ActivityDiagram) fitBars() {
+	for _, bar := range d.bars {
+		in, out := make([]shape.Shape, 0), make([]shape.Shape, 0)
+		for _, l := range d.links {
+			switch {
+			case l.from == bar:
+				out = append(out, l.to)
+			case l.to == bar:
+				in = append(in, l.from)
+			}
+		}
+		flows := out
+		if len(in) > len(out) {
+			flows = in
+		}
+		if len(flows) == 0 {
+			continue
+		}
+		left, right := centerX(flows[0]), centerX(flows[0])
+		for _, s := range flows[1:] {
+			cx := centerX(s)
+			if cx < left {
+				left = cx
+			}
+			if cx > right {
+				right = cx
+			}
+		}
+		margin := d.Spacing / 2
+		bar.SetWidth(right - left + 2*margin)
+		bar.SetX(left - margin)
+	}
+}
+
+func centerX(s shape.Shape) int {
+	x, _ := s.Position()
+	return x + s.Width()/2
+}
+
+// route updates the arrow and label of the link to the current
+// position of the linked shapes. Arrows to and from bars are
+// vertical if the bar is wide enough.
+func (d *ActivityDiagram) route(l *activityLink) {
+	a := shape.NewArrowBetween(l.from, l.to)
+	switch {
+	case d.isBar(l.from):
+		_, y := l.from.Position()
+		a.Start = barPoint(l.from, l.to, y+l.from.Height())
+		a.End = edge(l.to, a.Start)
+	case d.isBar(l.to):
+		_, y := l.to.Position()
+		a.End = barPoint(l.to, l.from, y)
+		a.Start = edge(l.from, a.End)
+	}
+	l.arrow.Detach()
+	l.arrow.Start = a.Start
+	l.arrow.End = a.End
+	l.arrow.PlaceLabels(d.nodes...)
+}
+
+// routeSide routes the link with orthogonal segments around all
+// activities and labels between the linked shapes on the side of the link.
+func (d *ActivityDiagram) routeSide(l *activityLink) {
+	start, end := sidePoint(l.from, l.side), sidePoint(l.to, l.side)
+	top, bottom := start.Y, end.Y
+	if top > bottom {
+		top, bottom = bottom, top
+	}
+	obstacles := append([]shape.Shape{}, d.nodes...)
+	for _, o := range d.links {
+		if o.side == 0 && o.label != nil {
+			obstacles = append(obstacles, o.label)
+		}
+	}
+	x := start.X
+	for _, s := range obstacles {
+		sx, sy := s.Position()
+		if sy > bottom || sy+s.Height() < top {
+			continue
+		}
+		if l.side > 0 && sx+s.Width() > x {
+			x = sx + s.Width()
+		}
+		if l.side < 0 && sx < x {
+			x = sx
+		}
+	}
+	if l.side > 0 && end.X > x {
+		x = end.X
+	}
+	if l.side < 0 && end.X < x {
+		x = end.X
+	}
+	x += l.side * d.Spacing / 2
+	l.arrow.Detach()
+	l.arrow.Start = start
+	l.arrow.End = end
+	l.arrow.Via = []xy.Point{{X: x, Y: start.Y}, {X: x, Y: end.Y}}
+}
+
+// sidePoint returns the middle point of the right, side > 0, or left
+// side of s.
+func sidePoint(s shape.Shape, side int) xy.Point {
+	x, y := s.Position()
+	if side > 0 {
+		x += s.Width()
+	}
+	return xy.Point{X: x, Y: y + s.Height()/2}
+}
+
+// barPoint returns the point at y on the bar closest to the center
+// of other.
+func barPoint(bar, other shape.Shape, y int) xy.Point {
+	x, _ := bar.Position()
+	cx := centerX(other)
+	switch {
+	case cx < x:
+		cx = x
+	case cx > x+bar.Width():
+		cx = x + bar.Width()
+	}
+	return xy.Point{X: cx, Y: y}
+}
+
+// edge returns the point on the edge of s towards p, or the center
+// of s if it has no edge.
+func edge(s shape.Shape, p xy.Point) xy.Point {
+	if e, ok := s.(shape.Edge); ok {
+		return e.Edge(p)
+	}
+	x, y := s.Position()
+	return xy.Point{X: x + s.Width()/2, Y: y + s.Height()/2}
+}
+
+// SaveAs saves the diagram to filename as SVG
+func (d *ActivityDiagram) SaveAs(filename string) error {
+	return saveAs(d, d.Style, filename)
+}
+
+// Inline returns rendered SVG with inlined style
+func (d *ActivityDiagram) Inline() string {
+	return draw.Inline(d, d.Style)
+}
+
+// String returns rendered SVG
+func (d *ActivityDiagram) String() string { return toString(d) }
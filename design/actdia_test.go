@@ -0,0 +1,187 @@
+package design
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/shape"
+)
+
+func TestActivityDiagram_Fork(t *testing.T) {
+	d := NewActivityDiagram()
+	d.Start().At(80, 20)
+	fork := d.Fork()
+	d.Branch(fork, "Compile")
+	a := d.Last()
+	d.Branch(fork, "Lint")
+	b := d.Last()
+	d.Branch(fork, "Test")
+	d.Then("Coverage")
+	c := d.Last()
+	join := d.Join(a, b, c)
+	d.Exit()
+	d.WriteSVG(ioutil.Discard)
+
+	assert := asserter.New(t)
+	assert(fork.Width() > centerX(c)-centerX(a)).Error("fork bar does not span branches")
+	_, jy := join.Position()
+	_, cy := c.Position()
+	assert(jy > cy+c.Height()).Error("join above lowest branch")
+	for _, l := range d.links {
+		if l.to == join {
+			assert().Equals(l.arrow.Start.X, l.arrow.End.X)
+		}
+	}
+}
+
+func TestActivityDiagram_Merge(t *testing.T) {
+	d := NewActivityDiagram()
+	d.Start().At(80, 20)
+	dec := d.Decide()
+	d.Then("Retry", "failed")
+	retry := d.Last()
+	ok := shape.NewState("Continue")
+	d.If(dec, "ok", ok)
+	m := d.Merge(retry, ok)
+	d.Exit()
+
+	assert := asserter.New(t)
+	in := make([]shape.Shape, 0)
+	for _, l := range d.links {
+		if l.to == m {
+			in = append(in, l.from)
+		}
+	}
+	assert().Equals(len(in), 2)
+	assert(in[0] == retry && in[1] == ok).Error("merge not linked from branches")
+	_, my := m.Position()
+	for _, end := range []shape.Shape{retry, ok} {
+		_, y := end.Position()
+		assert(my > y+end.Height()).Error("merge above", end)
+	}
+	assert().Equals(centerX(m), centerX(retry))
+}
+
+func TestActivityDiagram_Until(t *testing.T) {
+	d := NewActivityDiagram()
+	d.Start().At(80, 20)
+	d.Then("Read")
+	read := d.Last()
+	d.Then("Parse")
+	dec := d.Until(read, "more")
+	d.Exit()
+	d.WriteSVG(ioutil.Discard)
+
+	assert := asserter.New(t)
+	assert(d.Last() != dec).Error("flow not continued below decision")
+	back := d.links[len(d.links)-2]
+	assert().Equals(back.to, read)
+	assert().Equals(len(back.arrow.Via), 2)
+	rx, _ := read.Position()
+	for _, p := range back.arrow.Via {
+		assert(p.X > rx+read.Width()).Error("back-edge crosses activity", p)
+	}
+	lx, _ := back.label.Position()
+	assert(lx > back.arrow.Via[0].X).Error("label not next to back-edge")
+}
+
+func TestActivityDiagram_While(t *testing.T) {
+	d := NewActivityDiagram()
+	d.Start().At(80, 20)
+	loop := d.While()
+	d.Then("Wait", "pending")
+	wait := d.Last()
+	d.EndWhile(loop, "done")
+	d.Then("Go")
+	d.WriteSVG(ioutil.Discard)
+
+	assert := asserter.New(t)
+	var back, exit *activityLink
+	for _, l := range d.links {
+		switch {
+		case l.from == wait && l.to == loop:
+			back = l
+		case l.from == loop && l.to == d.Last():
+			exit = l
+		}
+	}
+	assert(back != nil && back.side < 0).Fatal("missing back-edge on left side")
+	assert(exit != nil && exit.side > 0).Fatal("missing exit on right side")
+	_, gy := d.Last().Position()
+	_, wy := wait.Position()
+	assert(gy > wy).Error("exit activity not below body")
+	wx, _ := wait.Position()
+	assert(back.arrow.Via[0].X < wx).Error("back-edge crosses body")
+	if !strings.Contains(d.Inline(), "done") {
+		t.Error("missing exit label")
+	}
+}
+
+func TestActivityDiagram_Lane(t *testing.T) {
+	d := NewActivityDiagram()
+	user := d.Lane("User")
+	d.Start().At(20, 20)
+	d.Then("Login")
+	login := d.Last()
+	sys := d.Lane("System")
+	d.Then("Verify")
+	verify := d.Last()
+	d.InLane(user)
+	d.Then("Browse")
+	browse := d.Last()
+	d.Exit()
+
+	assert := asserter.New(t)
+	assert().Equals(len(user.shapes), 4)
+	assert().Equals(len(sys.shapes), 1)
+
+	got := d.String()
+	assert().Contains(got, `class="lane"`)
+	lx, _ := login.Position()
+	vx, _ := verify.Position()
+	bx, _ := browse.Position()
+	assert(vx > lx+login.Width()).Error("verify not in lane to the right")
+	assert(bx < vx).Error("browse not back in first lane")
+
+	// writing again neither moves activities nor duplicates lanes
+	assert().Equals(d.String(), got)
+	assert().Equals(strings.Count(got, `class="lane"`), 2)
+}
+
+func TestActivityDiagram_Lane_default(t *testing.T) {
+	d := NewActivityDiagram()
+	d.Start().At(20, 20)
+	d.Then("Login")
+	login := d.Last()
+	d.Lane("System")
+	d.Then("Verify")
+	verify := d.Last()
+
+	assert := asserter.New(t)
+	assert().Equals(len(d.lanes), 2)
+	assert().Equals(len(d.lanes[0].shapes), 2)
+	got := d.String()
+	assert().Equals(strings.Count(got, `class="lane"`), 2)
+	lx, _ := login.Position()
+	vx, _ := verify.Position()
+	assert(vx > lx+login.Width()).Error("verify not in lane to the right")
+}
+
+func TestActivityDiagram_Inline(t *testing.T) {
+	d := NewActivityDiagram()
+	d.Lane("Ops")
+	d.Start().At(20, 20)
+	fork := d.Fork()
+	d.Branch(fork, "a")
+	a := d.Last()
+	d.Branch(fork, "b")
+	d.Join(a, d.Last())
+	d.Merge(d.Last())
+	d.Exit()
+	got := d.Inline()
+	if strings.Contains(got, "class") {
+		t.Error("found class attributes\n", got)
+	}
+}
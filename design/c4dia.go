@@ -0,0 +1,421 @@
+package design
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gregoryv/draw"
+	"github.com/gregoryv/draw/shape"
+	"github.com/gregoryv/draw/xy"
+	"github.com/gregoryv/nexus"
+)
+
+// NewC4Diagram returns an empty diagram for the C4 model. Use it
+// for system context, container and component diagrams, ie. level 1
+// to 3.
+func NewC4Diagram() *C4Diagram {
+	return &C4Diagram{
+		Diagram: NewDiagram(),
+	}
+}
+
+// C4Diagram shows people, software systems, containers and
+// components with relations between them. Elements are placed like
+// any shape, boundaries and relations are drawn when the diagram is
+// written.
+type C4Diagram struct {
+	*Diagram
+
+	boundaries []*C4Boundary
+	relations  []*C4Relation
+	linked     int // relations with arrows placed
+}
+
+// Boundary adds a dashed boundary around the given elements, kind
+// is e.g. "Software System" or "Container".
+func (d *C4Diagram) Boundary(name, kind string, elements ...*C4Element) *C4Boundary {
+	b := &C4Boundary{
+		Name:     name,
+		Kind:     kind,
+		Elements: elements,
+	}
+	d.boundaries = append(d.boundaries, b)
+	return b
+}
+
+// Rel adds a relation described by txt and optional technology,
+// e.g. "Reads from" using "SQL/TCP".
+func (d *C4Diagram) Rel(from, to *C4Element, txt, technology string) *C4Relation {
+	r := &C4Relation{
+		From:        from,
+		To:          to,
+		Description: txt,
+		Technology:  technology,
+	}
+	d.relations = append(d.relations, r)
+	return r
+}
+
+// WriteSVG renders the diagram as SVG to the given writer.
+func (d *C4Diagram) WriteSVG(w io.Writer) error {
+	// arrows follow their elements, so each relation is linked once
+	for _, r := range d.relations[d.linked:] {
+		d.link(r)
+	}
+	d.linked = len(d.relations)
+	if d.Legends == nil {
+		d.Legends = d.legends()
+	}
+	// boundaries fit the current position of their elements and
+	// are drawn behind them
+	content := d.Content
+	d.Content = append(d.boundaryShapes(), content...)
+	defer func() { d.Content = content }()
+	return d.Diagram.WriteSVG(w)
+}
+
+// boundaryShapes returns the rectangle and label of each boundary,
+// later boundaries first.
+func (d *C4Diagram) boundaryShapes() []draw.SVGWriter {
+	shapes := make([]draw.SVGWriter, 0, 2*len(d.boundaries))
+	for _, b := range d.boundaries {
+		rect, label := b.shapes(d.Font, d.Spacing)
+		shapes = append([]draw.SVGWriter{rect, label}, shapes...)
+	}
+	return shapes
+}
+
+// link places the arrow of the relation with its description and
+// technology as labels beside it.
+func (d *C4Diagram) link(r *C4Relation) {
+	a := shape.NewArrowBetween(r.From, r.To)
+	a.SetClass("c4-rel")
+	d.Place(a)
+	a.AddLabel(r.Description, shape.LabelMiddle)
+	if r.Technology != "" {
+		tech := a.AddLabel("["+r.Technology+"]", shape.LabelMiddle)
+		tech.Font.Height = d.Font.Height * 5 / 6
+		tech.SetClass("c4-tech")
+	}
+}
+
+// legends returns a caption for each kind of element in the
+// diagram.
+func (d *C4Diagram) legends() map[string]string {
+	legends := make(map[string]string)
+	for _, s := range d.Content {
+		if e, ok := s.(*C4Element); ok {
+			legends[e.class()] = e.legend()
+		}
+	}
+	return legends
+}
+
+// SaveAs saves the diagram to filename as SVG
+func (d *C4Diagram) SaveAs(filename string) error {
+	return saveAs(d, d.Diagram.Style, filename)
+}
+
+// Inline returns rendered SVG with inlined style
+func (d *C4Diagram) Inline() string {
+	return draw.Inline(d, d.Diagram.Style)
+}
+
+// String returns rendered SVG
+func (d *C4Diagram) String() string { return toString(d) }
+
+// C4Kind is the type of a C4 element.
+type C4Kind int
+
+const (
+	C4Person C4Kind = iota
+	C4SoftwareSystem
+	C4Container
+	C4Component
+)
+
+func (k C4Kind) String() string {
+	switch k {
+	case C4Person:
+		return "Person"
+	case C4SoftwareSystem:
+		return "Software System"
+	case C4Container:
+		return "Container"
+	default:
+		return "Component"
+	}
+}
+
+// NewC4Person returns a C4 person, e.g. a user of a system.
+func NewC4Person(name, description string) *C4Element {
+	return newC4Element(C4Person, name, "", description)
+}
+
+// NewC4SoftwareSystem returns a C4 software system.
+func NewC4SoftwareSystem(name, description string) *C4Element {
+	return newC4Element(C4SoftwareSystem, name, "", description)
+}
+
+// NewC4Container returns a C4 container, e.g. an application or data
+// store, using the given technology.
+func NewC4Container(name, technology, description string) *C4Element {
+	return newC4Element(C4Container, name, technology, description)
+}
+
+// NewC4Component returns a C4 component within a container.
+func NewC4Component(name, technology, description string) *C4Element {
+	return newC4Element(C4Component, name, technology, description)
+}
+
+func newC4Element(kind C4Kind, name, technology, description string) *C4Element {
+	return &C4Element{
+		Kind:        kind,
+		Name:        name,
+		Technology:  technology,
+		Description: description,
+		Font:        shape.DefaultFont,
+		Pad:         shape.DefaultPad,
+		width:       160,
+	}
+}
+
+// C4Element is a shape of a person, software system, container or
+// component showing its name, kind, technology and description.
+type C4Element struct {
+	x, y int
+
+	Kind        C4Kind
+	Name        string
+	Technology  string
+	Description string
+
+	// External elements are outside the scope of the diagram and
+	// shown in gray.
+	External bool
+
+	Font  shape.Font
+	Pad   shape.Padding
+	width int
+	cls   string
+}
+
+func (e *C4Element) String() string {
+	return fmt.Sprintf("%v %q", e.Kind, e.Name)
+}
+
+func (e *C4Element) Position() (int, int) { return e.x, e.y }
+func (e *C4Element) SetX(x int)           { e.x = x }
+func (e *C4Element) SetY(y int)           { e.y = y }
+func (e *C4Element) Direction() shape.Direction {
+	return shape.DirectionRight
+}
+
+// SetClass overrides the class given by the kind.
+func (e *C4Element) SetClass(c string)    { e.cls = c }
+func (e *C4Element) SetFont(f shape.Font) { e.Font = f }
+
+// SetWidth sets the width of the element, the description is
+// wrapped to fit.
+func (e *C4Element) SetWidth(w int) { e.width = w }
+
+func (e *C4Element) Width() int {
+	w := e.Pad.Left + e.Font.TextWidth(e.Name) + e.Pad.Right
+	if w < e.width {
+		return e.width
+	}
+	return w
+}
+
+func (e *C4Element) Height() int {
+	return e.head() + e.Pad.Top + len(e.lines())*e.Font.LineHeight + e.Pad.Bottom
+}
+
+// head returns the height of the head above the box of a person.
+func (e *C4Element) head() int {
+	if e.Kind == C4Person {
+		return 2*e.radius() - e.radius()/3
+	}
+	return 0
+}
+
+func (e *C4Element) radius() int { return e.Font.LineHeight }
+
+// Edge returns intersecting position of a line starting at start and
+// pointing to the element center.
+func (e *C4Element) Edge(start xy.Point) xy.Point {
+	box := shape.NewRect("")
+	box.SetX(e.x)
+	box.SetY(e.y + e.head())
+	box.SetWidth(e.Width())
+	box.SetHeight(e.Height() - e.head())
+	return box.Edge(start)
+}
+
+// class returns the class of the element depending on its kind.
+func (e *C4Element) class() string {
+	switch {
+	case e.cls != "":
+		return e.cls
+	case e.External:
+		return "c4-external"
+	case e.Kind == C4Person:
+		return "c4-person"
+	case e.Kind == C4SoftwareSystem:
+		return "c4-system"
+	case e.Kind == C4Container:
+		return "c4-container"
+	}
+	return "c4-component"
+}
+
+// legend returns the caption of the element kind in a legend.
+func (e *C4Element) legend() string {
+	if e.External {
+		return "External"
+	}
+	return e.Kind.String()
+}
+
+// kindLine returns the kind of the element with optional technology
+// in brackets.
+func (e *C4Element) kindLine() string {
+	if e.Technology == "" {
+		return "[" + e.Kind.String() + "]"
+	}
+	return fmt.Sprintf("[%v: %s]", e.Kind, e.Technology)
+}
+
+// lines returns the text lines below the name.
+func (e *C4Element) lines() []string {
+	lines := []string{e.Name, e.kindLine()}
+	if e.Description == "" {
+		return lines
+	}
+	lines = append(lines, "")
+	return append(lines, wrapText(e.Font, e.Description, e.Width()-e.Pad.Left-e.Pad.Right)...)
+}
+
+func (e *C4Element) WriteSVG(out io.Writer) error {
+	w, err := nexus.NewPrinter(out)
+	class := e.class()
+	if e.Kind == C4Person {
+		head := shape.NewCircle(e.radius())
+		head.SetClass(class)
+		head.SetX(e.x + e.Width()/2 - e.radius())
+		head.SetY(e.y)
+		head.WriteSVG(w)
+		w.Print("\n")
+	}
+	box := shape.NewRect("")
+	box.SetClass(class)
+	box.SetX(e.x)
+	box.SetY(e.y + e.head())
+	box.SetWidth(e.Width())
+	box.SetHeight(e.Height() - e.head())
+	box.WriteSVG(w)
+	y := e.y + e.head() + e.Pad.Top
+	for i, txt := range e.lines() {
+		label := shape.NewLabel(txt)
+		label.Font = e.Font
+		switch i {
+		case 0:
+			label.SetClass(class + "-title")
+		default:
+			label.SetClass(class + "-text")
+		}
+		label.SetX(e.x + (e.Width()-label.Width())/2)
+		label.SetY(y)
+		label.WriteSVG(w)
+		w.Print("\n")
+		y += e.Font.LineHeight
+	}
+	return *err
+}
+
+// wrapText splits txt into lines no wider than width.
+func wrapText(f shape.Font, txt string, width int) []string {
+	lines := make([]string, 0)
+	var line string
+	for _, word := range strings.Fields(txt) {
+		next := word
+		if line != "" {
+			next = line + " " + word
+		}
+		if line != "" && f.TextWidth(next) > width {
+			lines = append(lines, line)
+			next = word
+		}
+		line = next
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// C4Boundary groups elements, e.g. the containers of a software
+// system.
+type C4Boundary struct {
+	Name     string
+	Kind     string
+	Elements []*C4Element
+}
+
+// Add adds elements to the boundary.
+func (b *C4Boundary) Add(elements ...*C4Element) {
+	b.Elements = append(b.Elements, elements...)
+}
+
+// shapes returns the dashed rectangle surrounding all elements and
+// its label.
+func (b *C4Boundary) shapes(f shape.Font, pad int) (*shape.Rect, *shape.Label) {
+	label := shape.NewLabel(b.Name)
+	if b.Kind != "" {
+		label = shape.NewLabel(fmt.Sprintf("%s [%s]", b.Name, b.Kind))
+	}
+	label.Font = f
+	label.SetClass("c4-boundary-title")
+	rect := shape.NewRect("")
+	rect.SetClass("c4-boundary")
+	if len(b.Elements) == 0 {
+		return rect, label
+	}
+	x1, y1 := b.Elements[0].Position()
+	x2, y2 := x1, y1
+	for _, e := range b.Elements {
+		x, y := e.Position()
+		if x < x1 {
+			x1 = x
+		}
+		if y < y1 {
+			y1 = y
+		}
+		if v := x + e.Width(); v > x2 {
+			x2 = v
+		}
+		if v := y + e.Height(); v > y2 {
+			y2 = v
+		}
+	}
+	x1, y1 = x1-pad/2, y1-pad/2
+	x2, y2 = x2+pad/2, y2+pad/2+label.Height()
+	rect.SetX(x1)
+	rect.SetY(y1)
+	if w := label.Width() + pad; x2-x1 < w {
+		x2 = x1 + w
+	}
+	rect.SetWidth(x2 - x1)
+	rect.SetHeight(y2 - y1)
+	label.SetX(x1 + pad/4)
+	label.SetY(y2 - label.Height() - pad/4)
+	return rect, label
+}
+
+// C4Relation describes how one element uses another.
+type C4Relation struct {
+	From, To    *C4Element
+	Description string
+	Technology  string
+}
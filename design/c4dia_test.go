@@ -0,0 +1,78 @@
+package design
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+)
+
+func TestC4Diagram(t *testing.T) {
+	var (
+		d    = NewC4Diagram()
+		user = NewC4Person("User", "")
+		app  = NewC4Container("App", "Go", "Serves the API to all clients of the system")
+		db   = NewC4Container("Store", "PostgreSQL", "")
+		ext  = NewC4SoftwareSystem("Mail", "")
+	)
+	ext.External = true
+	d.Place(user).At(20, 20)
+	d.Place(app).Below(user, 60)
+	d.Place(db).RightOf(app, 60)
+	d.Place(ext).Below(app, 60)
+	b := d.Boundary("System", "Software System", app)
+	b.Add(db)
+	d.Rel(user, app, "Uses", "HTTPS")
+	d.Rel(app, db, "Reads", "")
+
+	assert := asserter.New(t)
+	assert().Equals(app.kindLine(), "[Container: Go]")
+	assert().Equals(ext.class(), "c4-external")
+	assert().Equals(len(app.lines()), 5)
+	assert().Equals(d.legends(), map[string]string{
+		"c4-person":    "Person",
+		"c4-container": "Container",
+		"c4-external":  "External",
+	})
+
+	rect, _ := b.shapes(d.Font, d.Spacing)
+	ax, ay := app.Position()
+	assert(rect.X < ax && rect.Y < ay).Error("app outside boundary")
+	dx, _ := db.Position()
+	assert(rect.X+rect.Width() > dx+db.Width()).Error("store outside boundary")
+
+	got := d.String()
+	assert().Contains(got, "System [Software System]")
+	assert().Contains(got, "[HTTPS]")
+	assert().Contains(got, `class="c4-boundary"`)
+
+	// writing again neither duplicates boundaries nor relations
+	assert().Equals(d.String(), got)
+	assert().Equals(strings.Count(got, `class="c4-boundary"`), 1)
+	assert().Equals(strings.Count(got, "[HTTPS]"), 1)
+}
+
+func TestC4Diagram_Inline(t *testing.T) {
+	var (
+		d    = NewC4Diagram()
+		user = NewC4Person("User", "A user")
+		sys  = NewC4SoftwareSystem("System", "Does things")
+		comp = NewC4Component("Parser", "Go", "")
+	)
+	d.Place(user).At(20, 20)
+	d.Place(sys).RightOf(user, 60)
+	d.Place(comp).Below(sys, 60)
+	d.Boundary("System", "", comp)
+	d.Rel(user, sys, "Uses", "HTTPS")
+	got := d.Inline()
+	if strings.Contains(got, "class") {
+		t.Error("found class attributes\n", got)
+	}
+}
+
+func Test_wrapText(t *testing.T) {
+	got := wrapText(NewDiagram().Font, "one two three four", 70)
+	assert := asserter.New(t)
+	assert().Equals(got, []string{"one two", "three four"})
+	assert().Equals(len(wrapText(NewDiagram().Font, "", 50)), 0)
+}
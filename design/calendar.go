@@ -0,0 +1,203 @@
+package design
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gregoryv/draw/types/date"
+)
+
+// NewCalendar returns a calendar with a Monday to Friday work week
+// and no holidays.
+func NewCalendar() *Calendar {
+	c := &Calendar{holidays: make(map[string]bool)}
+	c.SetWorkWeek(time.Monday, time.Tuesday, time.Wednesday,
+		time.Thursday, time.Friday)
+	return c
+}
+
+// Calendar defines working days used when scheduling tasks in
+// business days. A nil calendar has no days off and an empty work
+// week, as in the zero value, makes every day but holidays a working
+// day.
+type Calendar struct {
+	workdays [7]bool
+	holidays map[string]bool // yyyymmdd
+}
+
+// SetWorkWeek sets the working days of each week.
+func (c *Calendar) SetWorkWeek(days ...time.Weekday) {
+	c.workdays = [7]bool{}
+	for _, d := range days {
+		c.workdays[d] = true
+	}
+}
+
+// AddHoliday adds days off.
+func (c *Calendar) AddHoliday(days ...time.Time) {
+	if c.holidays == nil {
+		c.holidays = make(map[string]bool)
+	}
+	for _, d := range days {
+		c.holidays[dayKey(d)] = true
+	}
+}
+
+func dayKey(t time.Time) string { return t.Format("20060102") }
+
+// IsHoliday returns true if t is a holiday.
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	return c != nil && c.holidays[dayKey(t)]
+}
+
+// IsWorkday returns true if t is a working day, ie. in the work week
+// and not a holiday.
+func (c *Calendar) IsWorkday(t time.Time) bool {
+	if c == nil {
+		return true
+	}
+	anyday := c.workdays == [7]bool{}
+	return (anyday || c.workdays[t.Weekday()]) && !c.IsHoliday(t)
+}
+
+// AddWorkdays returns the day after n working days counted from t.
+// For negative n the first of n working days before t is returned.
+func (c *Calendar) AddWorkdays(t time.Time, n int) time.Time {
+	if c == nil {
+		return t.AddDate(0, 0, n)
+	}
+	for ; n > 0; t = t.AddDate(0, 0, 1) {
+		if c.IsWorkday(t) {
+			n--
+		}
+	}
+	for ; n < 0; n++ {
+		t = t.AddDate(0, 0, -1)
+		for !c.IsWorkday(t) {
+			t = t.AddDate(0, 0, -1)
+		}
+	}
+	return t
+}
+
+// Workdays returns the number of working days from, inclusive, to
+// to. The result is negative if to is before from.
+func (c *Calendar) Workdays(from, to time.Time) int {
+	if to.Before(from) {
+		return -c.Workdays(to, from)
+	}
+	if c == nil {
+		return daysIn(to.Sub(from))
+	}
+	var n int
+	for t := from; t.Before(to); t = t.AddDate(0, 0, 1) {
+		if c.IsWorkday(t) {
+			n++
+		}
+	}
+	return n
+}
+
+func daysIn(d time.Duration) int {
+	return int(math.Round(d.Hours() / 24))
+}
+
+// nextWorkday returns t or the first working day after it.
+func (c *Calendar) nextWorkday(t time.Time) time.Time {
+	if c == nil {
+		return t
+	}
+	for !c.IsWorkday(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// LoadHolidays adds holidays from the given iCalendar file, if it
+// ends with .ics, or CSV file. See ReadICS and ReadCSV.
+func (c *Calendar) LoadHolidays(filename string) error {
+	fh, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	if strings.EqualFold(filepath.Ext(filename), ".ics") {
+		err = c.ReadICS(fh)
+	} else {
+		err = c.ReadCSV(fh)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return nil
+}
+
+// ReadICS adds each day of all events in the iCalendar data as
+// holidays.
+func (c *Calendar) ReadICS(r io.Reader) error {
+	events, err := readICS(r)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		if e.kind != "VEVENT" {
+			continue
+		}
+		from, err := e.date("DTSTART")
+		if err != nil {
+			return err
+		}
+		to := from.AddDate(0, 0, 1)
+		if _, found := e.props["DTEND"]; found {
+			if to, err = e.endDay("DTEND"); err != nil {
+				return err
+			}
+		}
+		for t := from; t.Before(to); t = t.AddDate(0, 0, 1) {
+			c.AddHoliday(t)
+		}
+	}
+	return nil
+}
+
+// ReadCSV adds holidays from CSV data where the first column is a
+// date as yyyymmdd or yyyy-mm-dd. Fields may be quoted. Other
+// columns, e.g. names, empty lines and lines starting with # are
+// ignored.
+func (c *Calendar) ReadCSV(r io.Reader) error {
+	s := bufio.NewScanner(r)
+	var line int
+	for s.Scan() {
+		line++
+		v := strings.TrimSpace(s.Text())
+		if v == "" || strings.HasPrefix(v, "#") {
+			continue
+		}
+		// one record per line keeps line numbers in errors
+		rec, err := csv.NewReader(strings.NewReader(v)).Read()
+		if err != nil {
+			return fmt.Errorf("line %v: %w", line, err)
+		}
+		t, err := parseDay(strings.TrimSpace(rec[0]))
+		if err != nil {
+			return fmt.Errorf("line %v: %w", line, err)
+		}
+		c.AddHoliday(t)
+	}
+	return s.Err()
+}
+
+// parseDay parses absolute dates, see date.Parse.
+func parseDay(v string) (time.Time, error) {
+	if strings.HasPrefix(v, "+") || strings.HasPrefix(v, "-") {
+		return time.Time{}, fmt.Errorf("bad date %q", v)
+	}
+	return date.Parse(v, time.Time{})
+}
@@ -0,0 +1,173 @@
+package design
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/types/date"
+)
+
+func TestCalendar_AddWorkdays(t *testing.T) {
+	c := NewCalendar()
+	c.AddHoliday(date.String("20191225").Time())
+	ok := func(from date.String, n int, exp date.String) {
+		t.Helper()
+		got := c.AddWorkdays(from.Time(), n)
+		if !got.Equal(exp.Time()) {
+			t.Errorf("%s %+d: got %v, expected %s", from, n, got, exp)
+		}
+	}
+	ok("20191111", 5, "20191116")  // Monday to Saturday
+	ok("20191116", 1, "20191119")  // starting on a weekend
+	ok("20191116", -5, "20191111") // back to Monday
+	ok("20191223", 3, "20191227")  // skip holiday
+	ok("20191223", 0, "20191223")
+
+	var none *Calendar
+	got := none.AddWorkdays(date.String("20191111").Time(), 7)
+	if !got.Equal(date.String("20191118").Time()) {
+		t.Error("nil calendar skips days", got)
+	}
+	if !none.IsWorkday(date.String("20191116").Time()) {
+		t.Error("nil calendar has days off")
+	}
+}
+
+func TestCalendar_Workdays(t *testing.T) {
+	c := NewCalendar()
+	c.SetWorkWeek(time.Monday, time.Tuesday, time.Wednesday, time.Thursday)
+	a, b := date.String("20191111").Time(), date.String("20191118").Time()
+	assert := asserter.New(t)
+	assert().Equals(c.Workdays(a, b), 4)
+	assert().Equals(c.Workdays(b, a), -4)
+	var none *Calendar
+	assert().Equals(none.Workdays(a, b), 7)
+}
+
+func TestCalendar_LoadHolidays(t *testing.T) {
+	for _, filename := range []string{
+		"testdata/holidays.ics",
+		"testdata/holidays.csv",
+	} {
+		c := NewCalendar()
+		if err := c.LoadHolidays(filename); err != nil {
+			t.Fatal(err)
+		}
+		for _, day := range []date.String{
+			"20191224", "20191225", "20191226", "20191231",
+		} {
+			if !c.IsHoliday(day.Time()) {
+				t.Error(filename, "missing", day)
+			}
+		}
+		if c.IsHoliday(date.String("20191227").Time()) {
+			t.Error(filename, "DTEND is not a holiday")
+		}
+	}
+}
+
+func TestCalendar_ReadCSV_quoted(t *testing.T) {
+	c := NewCalendar()
+	err := c.ReadCSV(strings.NewReader(`"2024-12-25","Christmas, Day"
+2024-12-26,"Boxing ""Day"""
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, day := range []date.String{"20241225", "20241226"} {
+		if !c.IsHoliday(day.Time()) {
+			t.Error("missing", day)
+		}
+	}
+	err = c.ReadCSV(strings.NewReader("20191224\n\"2019,xmas\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Error("expected error with line number, got", err)
+	}
+}
+
+func TestCalendar_LoadHolidays_errors(t *testing.T) {
+	c := NewCalendar()
+	if err := c.LoadHolidays("testdata/no_such.csv"); err == nil {
+		t.Error("expected error for missing file")
+	}
+	err := c.ReadCSV(strings.NewReader("20191224\nxmas,2019\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Error("expected error with line number, got", err)
+	}
+	err = c.ReadICS(strings.NewReader("BEGIN:VEVENT\nDTSTART:2019\nEND:VEVENT"))
+	if err == nil {
+		t.Error("expected error for bad DTSTART")
+	}
+	err = c.ReadICS(strings.NewReader("BEGIN:VEVENT\nbroken\nEND:VEVENT"))
+	if err == nil {
+		t.Error("expected error for missing colon")
+	}
+}
+
+func TestCalendar_ReadICS_timed(t *testing.T) {
+	c := NewCalendar()
+	err := c.ReadICS(strings.NewReader(`BEGIN:VEVENT
+DTSTART:20191224T090000Z
+DTEND:20191224T120000Z
+END:VEVENT
+BEGIN:VEVENT
+DTSTART:20191230T220000
+DTEND:20191231T000000
+END:VEVENT`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for day, exp := range map[date.String]bool{
+		"20191224": true,
+		"20191225": false,
+		"20191230": true,
+		"20191231": false, // ends at midnight
+	} {
+		if got := c.IsHoliday(day.Time()); got != exp {
+			t.Error(day, "holiday", got)
+		}
+	}
+}
+
+func TestCalendar_zero(t *testing.T) {
+	var c Calendar
+	xmas := date.String("20191225").Time()
+	c.AddHoliday(xmas)
+	assert := asserter.New(t)
+	assert(c.IsHoliday(xmas)).Error("missing holiday")
+	sat := date.String("20191221").Time()
+	assert(c.IsWorkday(sat)).Error("empty work week has days off")
+	// every day but the holiday is a working day
+	assert().Equals(c.Workdays(sat, sat.AddDate(0, 0, 7)), 6)
+	assert().Equals(c.AddWorkdays(sat, 6), sat.AddDate(0, 0, 7))
+}
+
+func TestGanttChart_Calendar(t *testing.T) {
+	var (
+		d   = NewGanttChart("20191216", 21)
+		dev = d.Add("Develop")
+		rel = d.Add("Release")
+	)
+	d.Place(dev).At("20191219", 3)
+	d.Place(rel).After(dev, 3)
+	// the calendar applies to all tasks, even if set after placing
+	d.Calendar = NewCalendar()
+	d.Calendar.AddHoliday(date.String("20191225").Time())
+	d.schedule()
+	assert := asserter.New(t)
+	// Thursday, Friday and Monday, then Tuesday, Thursday and Friday
+	assert().Equals(dev.To(), date.String("20191224").Time())
+	assert().Equals(rel.From(), date.String("20191224").Time())
+	assert().Equals(rel.To(), date.String("20191228").Time())
+	assert().Equals(d.Slack(dev), 0)
+
+	got := d.String()
+	assert().Contains(got, `class="holiday"`)
+	assert().Contains(got, `class="weekend"`)
+	d.Weeks = true
+	if got := d.Inline(); strings.Contains(got, "class") {
+		t.Error("found class attributes\n", got)
+	}
+}
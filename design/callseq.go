@@ -0,0 +1,227 @@
+package design
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+)
+
+// NewCallSequence returns a sequence diagram of the calls made by fn
+// in the package found in dir. fn is a function name, or a method
+// given as Type.Method or (*Type).Method. Calls are followed into
+// functions of the same package until depth is reached, only
+// statically resolvable calls are included, ie. calls through
+// interfaces or func values are skipped.
+//
+// Receiver types are shown as columns named as in
+// SequenceDiagram.AddStruct, plain functions use the package name.
+// A package that fails to type check results in an error listing
+// all type errors.
+func NewCallSequence(dir, fn string, depth int) (*SequenceDiagram, error) {
+	fset := token.NewFileSet()
+	files, err := parseDir(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	var errs checkErrors
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		// collect all errors rather than stopping at the first
+		Error: func(err error) { errs = append(errs, err) },
+	}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, info)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	start, err := lookupFunc(pkg, fn)
+	if err != nil {
+		return nil, err
+	}
+	decls := make(map[*types.Func]*ast.FuncDecl)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok && decl.Body != nil {
+				if obj, ok := info.Defs[decl.Name].(*types.Func); ok {
+					decls[obj] = decl
+				}
+			}
+		}
+	}
+	t := &callTracer{
+		SequenceDiagram: NewSequenceDiagram(),
+		info:            info,
+		decls:           decls,
+		active:          make(map[*types.Func]bool),
+	}
+	t.column(columnName(start))
+	t.trace(start, depth)
+	return t.SequenceDiagram, nil
+}
+
+// checkErrors lists all type errors found in a package.
+type checkErrors []error
+
+func (e checkErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseDir parses the non test go files in dir matching the default
+// build context.
+func parseDir(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(bp.GoFiles) == 0 {
+		return nil, fmt.Errorf("no go files in %s", dir)
+	}
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// lookupFunc returns the function or method named by fn in pkg.
+func lookupFunc(pkg *types.Package, fn string) (*types.Func, error) {
+	parts := strings.Split(fn, ".")
+	switch len(parts) {
+	case 1:
+		if obj, ok := pkg.Scope().Lookup(fn).(*types.Func); ok {
+			return obj, nil
+		}
+	case 2:
+		typeName := strings.TrimSuffix(strings.TrimPrefix(parts[0], "(*"), ")")
+		obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+		if !ok {
+			break
+		}
+		ptr := types.NewPointer(obj.Type())
+		m, _, _ := types.LookupFieldOrMethod(ptr, true, pkg, parts[1])
+		if m, ok := m.(*types.Func); ok {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("%s not found in package %s", fn, pkg.Name())
+}
+
+// columnName returns the sequence diagram column for fn.
+func columnName(fn *types.Func) string {
+	sig := fn.Type().(*types.Signature)
+	if recv := sig.Recv(); recv != nil {
+		t := recv.Type()
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		if n, ok := t.(*types.Named); ok && n.Obj().Pkg() != nil {
+			return n.Obj().Pkg().Name() + "." + n.Obj().Name()
+		}
+	}
+	if fn.Pkg() == nil {
+		return "builtin"
+	}
+	return fn.Pkg().Name()
+}
+
+type callTracer struct {
+	*SequenceDiagram
+
+	info   *types.Info
+	decls  map[*types.Func]*ast.FuncDecl
+	active map[*types.Func]bool // on the call stack
+}
+
+// column adds the named column if missing.
+func (t *callTracer) column(name string) string {
+	for _, c := range t.columns {
+		if c == name {
+			return name
+		}
+	}
+	return t.Add(name)
+}
+
+// trace adds links for all calls made in the body of fn in the
+// order they are evaluated.
+func (t *callTracer) trace(fn *types.Func, depth int) {
+	decl, found := t.decls[fn]
+	if !found || depth < 1 || t.active[fn] {
+		return
+	}
+	t.active[fn] = true
+	defer delete(t.active, fn)
+
+	from := columnName(fn)
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // not called here
+		case *ast.CallExpr:
+			// arguments and receivers are evaluated first
+			ast.Inspect(n.Fun, visit)
+			for _, arg := range n.Args {
+				ast.Inspect(arg, visit)
+			}
+			if callee := t.callee(n); callee != nil {
+				to := t.column(columnName(callee))
+				t.Link(from, to, callee.Name()+"()")
+				t.trace(callee, depth-1)
+			}
+			return false
+		}
+		return true
+	}
+	ast.Inspect(decl.Body, visit)
+}
+
+// callee returns the statically resolved function of the call or nil.
+func (t *callTracer) callee(call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch fun := unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+	fn, ok := t.info.Uses[id].(*types.Func)
+	if !ok {
+		return nil // builtin, conversion or func value
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv != nil && types.IsInterface(recv.Type()) {
+		return nil
+	}
+	return fn
+}
+
+func unparen(e ast.Expr) ast.Expr {
+	for {
+		p, ok := e.(*ast.ParenExpr)
+		if !ok {
+			return e
+		}
+		e = p.X
+	}
+}
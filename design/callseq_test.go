@@ -0,0 +1,53 @@
+package design
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+)
+
+func TestNewCallSequence(t *testing.T) {
+	d, err := NewCallSequence("testdata/shop", "Run", 4)
+	assert := asserter.New(t)
+	assert(err == nil).Fatal(err)
+	assert().Equals(d.columns, []string{
+		"shop", "shop.Client", "strings", "shop.Server", "fmt",
+	})
+	got := make([]string, len(d.links))
+	for i, lnk := range d.links {
+		got[i] = d.columns[lnk.fromIndex] + " " + lnk.text
+	}
+	assert().Equals(got, []string{
+		"shop Buy()",
+		"shop.Client ToLower()",
+		"shop.Client Order()",
+		"shop.Server validate()",
+		"shop.Server Sprintf()",
+		"shop.Server Errorf()",
+	})
+}
+
+func TestNewCallSequence_depth(t *testing.T) {
+	d, err := NewCallSequence("testdata/shop", "(*Client).Buy", 1)
+	assert := asserter.New(t)
+	assert(err == nil).Fatal(err)
+	assert().Equals(len(d.links), 2)
+	assert().Equals(d.columns[0], "shop.Client")
+}
+
+func TestNewCallSequence_errors(t *testing.T) {
+	if _, err := NewCallSequence("testdata/shop", "Nothing", 1); err == nil {
+		t.Error("expected error for missing func")
+	}
+	if _, err := NewCallSequence("testdata/shop", "Server.nothing", 1); err == nil {
+		t.Error("expected error for missing method")
+	}
+	if _, err := NewCallSequence("testdata/nosuchdir", "Run", 1); err == nil {
+		t.Error("expected error for missing dir")
+	}
+	_, err := NewCallSequence("testdata/broken", "Run", 1)
+	if err == nil || strings.Count(err.Error(), "\n") != 1 {
+		t.Error("expected both type errors, got", err)
+	}
+}
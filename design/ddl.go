@@ -0,0 +1,543 @@
+package design
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"unicode"
+)
+
+// Table is a database table parsed from a CREATE TABLE statement.
+type Table struct {
+	Name        string
+	Columns     []*Column
+	PrimaryKey  []string
+	ForeignKeys []*ForeignKey
+	Unique      [][]string
+}
+
+// Column of a table.
+type Column struct {
+	Name    string
+	Type    string
+	NotNull bool
+}
+
+// ForeignKey references columns of another table.
+type ForeignKey struct {
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+// Column returns the named column or nil if not found.
+func (t *Table) Column(name string) *Column {
+	for _, c := range t.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// IsPrimary returns true if the named column is part of the primary
+// key.
+func (t *Table) IsPrimary(column string) bool {
+	return hasColumn(t.PrimaryKey, column)
+}
+
+// IsForeign returns true if the named column is part of a foreign
+// key.
+func (t *Table) IsForeign(column string) bool {
+	for _, fk := range t.ForeignKeys {
+		if hasColumn(fk.Columns, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUnique returns true if the named column alone is unique.
+func (t *Table) IsUnique(column string) bool {
+	for _, u := range t.Unique {
+		if len(u) == 1 && strings.EqualFold(u[0], column) {
+			return true
+		}
+	}
+	return false
+}
+
+// references returns true if t has a foreign key to the named
+// table.
+func (t *Table) references(table string) bool {
+	for _, fk := range t.ForeignKeys {
+		if strings.EqualFold(fk.RefTable, table) {
+			return true
+		}
+	}
+	return false
+}
+
+// isKey returns true if the columns together are the primary key or
+// a unique constraint.
+func (t *Table) isKey(columns []string) bool {
+	if sameColumns(t.PrimaryKey, columns) {
+		return true
+	}
+	for _, u := range t.Unique {
+		if sameColumns(u, columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// notNull returns true if none of the columns may be null.
+func (t *Table) notNull(columns []string) bool {
+	for _, name := range columns {
+		c := t.Column(name)
+		if c == nil || !(c.NotNull || t.IsPrimary(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasColumn(columns []string, name string) bool {
+	for _, c := range columns {
+		if strings.EqualFold(c, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameColumns(a, b []string) bool {
+	if len(a) == 0 || len(a) != len(b) {
+		return false
+	}
+	for _, c := range a {
+		if !hasColumn(b, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadDDL parses CREATE TABLE statements and foreign keys added with
+// ALTER TABLE. Other statements are ignored.
+func ReadDDL(r io.Reader) ([]*Table, error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDDL(string(src))
+}
+
+// ParseDDL parses the given SQL, see ReadDDL.
+func ParseDDL(src string) ([]*Table, error) {
+	tokens, err := tokenizeSQL(src)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]*Table, 0)
+	for _, stmt := range splitTokens(tokens, ";") {
+		p := &ddlParser{tokens: stmt}
+		switch {
+		case p.accept("CREATE") && p.table():
+			t, err := p.createTable()
+			if err != nil {
+				return tables, err
+			}
+			tables = append(tables, t)
+		case p.accept("ALTER", "TABLE"):
+			if err := p.alterTable(tables); err != nil {
+				return tables, err
+			}
+		}
+	}
+	names := make(map[string]bool)
+	for _, t := range tables {
+		names[strings.ToLower(t.Name)] = true
+	}
+	for _, t := range tables {
+		for _, fk := range t.ForeignKeys {
+			if !names[strings.ToLower(fk.RefTable)] {
+				return tables, fmt.Errorf(
+					"table %s: references unknown table %s", t.Name, fk.RefTable,
+				)
+			}
+		}
+	}
+	return tables, nil
+}
+
+type ddlParser struct {
+	tokens []string
+	i      int
+}
+
+// accept consumes the given keywords if they are next.
+func (p *ddlParser) accept(words ...string) bool {
+	if p.i+len(words) > len(p.tokens) {
+		return false
+	}
+	for j, w := range words {
+		if !strings.EqualFold(p.tokens[p.i+j], w) {
+			return false
+		}
+	}
+	p.i += len(words)
+	return true
+}
+
+func (p *ddlParser) next() string {
+	if p.i >= len(p.tokens) {
+		return ""
+	}
+	p.i++
+	return p.tokens[p.i-1]
+}
+
+func (p *ddlParser) peek() string {
+	if p.i >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.i]
+}
+
+// name returns the next identifier without quotes.
+func (p *ddlParser) name() (string, error) {
+	v := p.next()
+	switch v {
+	case "", "(", ")", ",":
+		return "", fmt.Errorf("expected name, got %q", v)
+	}
+	return unquoteIdent(v), nil
+}
+
+// group returns the tokens inside the next parenthesis.
+func (p *ddlParser) group() ([]string, error) {
+	if p.next() != "(" {
+		return nil, fmt.Errorf("expected (")
+	}
+	start, depth := p.i, 1
+	for ; p.i < len(p.tokens); p.i++ {
+		switch p.tokens[p.i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if depth == 0 {
+			p.i++
+			return p.tokens[start : p.i-1], nil
+		}
+	}
+	return nil, fmt.Errorf("missing )")
+}
+
+// names returns the identifiers in the next parenthesis.
+func (p *ddlParser) names() ([]string, error) {
+	group, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for _, v := range group {
+		if v != "," {
+			names = append(names, unquoteIdent(v))
+		}
+	}
+	return names, nil
+}
+
+// table accepts TABLE optionally preceded by TEMPORARY or similar.
+func (p *ddlParser) table() bool {
+	p.accept("TEMP")
+	p.accept("TEMPORARY")
+	p.accept("UNLOGGED")
+	return p.accept("TABLE")
+}
+
+func (p *ddlParser) createTable() (*Table, error) {
+	p.accept("IF", "NOT", "EXISTS")
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	t := &Table{Name: name}
+	defs, err := p.group()
+	if err != nil {
+		return nil, fmt.Errorf("table %s: %v", name, err)
+	}
+	for _, def := range splitTokens(defs, ",") {
+		d := &ddlParser{tokens: def}
+		if err := d.definition(t); err != nil {
+			return nil, fmt.Errorf("table %s: %v", name, err)
+		}
+	}
+	return t, nil
+}
+
+// alterTable adds constraints to an already created table.
+func (p *ddlParser) alterTable(tables []*Table) error {
+	p.accept("ONLY")
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	var t *Table
+	for _, v := range tables {
+		if strings.EqualFold(v.Name, name) {
+			t = v
+		}
+	}
+	if t == nil || !p.accept("ADD") {
+		return nil // nothing we show
+	}
+	if err := p.constraint(t); err != nil {
+		return fmt.Errorf("table %s: %v", name, err)
+	}
+	return nil
+}
+
+// definition parses a column or table constraint.
+func (p *ddlParser) definition(t *Table) error {
+	switch strings.ToUpper(p.peek()) {
+	case "CONSTRAINT", "PRIMARY", "FOREIGN", "UNIQUE", "CHECK":
+		return p.constraint(t)
+	case "KEY", "INDEX":
+		// MySQL indexes, not columns named key
+		if p.i+2 < len(p.tokens) && p.tokens[p.i+2] == "(" ||
+			p.i+1 < len(p.tokens) && p.tokens[p.i+1] == "(" {
+			return nil
+		}
+	}
+	return p.column(t)
+}
+
+// constraint parses table constraints, unknown ones are ignored.
+func (p *ddlParser) constraint(t *Table) error {
+	if p.accept("CONSTRAINT") {
+		if _, err := p.name(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		cols, err := p.names()
+		if err != nil {
+			return err
+		}
+		t.PrimaryKey = cols
+	case p.accept("UNIQUE"):
+		p.accept("KEY")
+		p.accept("INDEX")
+		if p.peek() != "(" {
+			p.next() // index name
+		}
+		cols, err := p.names()
+		if err != nil {
+			return err
+		}
+		t.Unique = append(t.Unique, cols)
+	case p.accept("FOREIGN", "KEY"):
+		cols, err := p.names()
+		if err != nil {
+			return err
+		}
+		return p.references(t, cols)
+	}
+	return nil
+}
+
+// references parses REFERENCES table [(columns)].
+func (p *ddlParser) references(t *Table, cols []string) error {
+	if !p.accept("REFERENCES") {
+		return fmt.Errorf("expected REFERENCES")
+	}
+	ref, err := p.name()
+	if err != nil {
+		return err
+	}
+	fk := &ForeignKey{Columns: cols, RefTable: ref}
+	if p.peek() == "(" {
+		if fk.RefColumns, err = p.names(); err != nil {
+			return err
+		}
+	}
+	t.ForeignKeys = append(t.ForeignKeys, fk)
+	return nil
+}
+
+// column parses a column with its type and inline constraints.
+func (p *ddlParser) column(t *Table) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	c := &Column{Name: name}
+	t.Columns = append(t.Columns, c)
+	typ := make([]string, 0)
+	for p.peek() != "" && !columnConstraint[strings.ToUpper(p.peek())] {
+		v := p.next()
+		if v == "(" && len(typ) > 0 {
+			p.i--
+			args, err := p.group()
+			if err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+			typ[len(typ)-1] += "(" + strings.Join(args, "") + ")"
+			continue
+		}
+		typ = append(typ, v)
+	}
+	c.Type = strings.ToLower(strings.Join(typ, " "))
+	for p.peek() != "" {
+		switch {
+		case p.accept("NOT", "NULL"):
+			c.NotNull = true
+		case p.accept("PRIMARY", "KEY"):
+			t.PrimaryKey = []string{name}
+		case p.accept("UNIQUE"):
+			t.Unique = append(t.Unique, []string{name})
+		case strings.EqualFold(p.peek(), "REFERENCES"):
+			if err := p.references(t, []string{name}); err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+		case p.peek() == "(":
+			p.group() // e.g. CHECK (...)
+		default:
+			p.next() // DEFAULT values, collations etc.
+		}
+	}
+	return nil
+}
+
+var columnConstraint = map[string]bool{
+	"NOT": true, "NULL": true, "PRIMARY": true, "UNIQUE": true,
+	"REFERENCES": true, "DEFAULT": true, "CHECK": true,
+	"CONSTRAINT": true, "COLLATE": true, "AUTO_INCREMENT": true,
+	"AUTOINCREMENT": true, "GENERATED": true, "IDENTITY": true,
+}
+
+// tokenizeSQL splits src into words, quoted identifiers, string
+// literals and punctuation. Comments are removed.
+func tokenizeSQL(src string) ([]string, error) {
+	tokens := make([]string, 0)
+	rs := []rune(src)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+		case r == '-' && i+1 < len(rs) && rs[i+1] == '-':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			i += 2
+			for i+1 < len(rs) && !(rs[i] == '*' && rs[i+1] == '/') {
+				i++
+			}
+			if i+1 >= len(rs) {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i++
+		case r == '(' || r == ')' || r == ',' || r == ';':
+			tokens = append(tokens, string(r))
+		default:
+			// words and identifiers, possibly quoted and qualified
+			j := i
+			for {
+				end, err := wordEnd(rs, j)
+				if err != nil {
+					return nil, err
+				}
+				j = end
+				if j >= len(rs) || rs[j] != '.' {
+					break
+				}
+				j++
+			}
+			tokens = append(tokens, string(rs[i:j]))
+			i = j - 1
+		}
+	}
+	return tokens, nil
+}
+
+// wordEnd returns the index after the word or quoted string
+// starting at i.
+func wordEnd(rs []rune, i int) (int, error) {
+	if i >= len(rs) {
+		return i, nil
+	}
+	switch closing := rs[i]; closing {
+	case '[':
+		closing = ']'
+		fallthrough
+	case '\'', '"', '`':
+		j := i + 1
+		for j < len(rs) && rs[j] != closing {
+			j++
+		}
+		if j == len(rs) {
+			return j, fmt.Errorf("unterminated %c", rs[i])
+		}
+		return j + 1, nil
+	}
+	j := i
+	for j < len(rs) && !unicode.IsSpace(rs[j]) &&
+		!strings.ContainsRune("(),;.'\"`[", rs[j]) {
+		j++
+	}
+	return j, nil
+}
+
+// splitTokens splits tokens on sep outside of parenthesis. Empty
+// parts are left out.
+func splitTokens(tokens []string, sep string) [][]string {
+	parts := make([][]string, 0)
+	var depth, start int
+	for i, v := range tokens {
+		switch v {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case sep:
+			if depth == 0 {
+				if i > start {
+					parts = append(parts, tokens[start:i])
+				}
+				start = i + 1
+			}
+		}
+	}
+	if start < len(tokens) {
+		parts = append(parts, tokens[start:])
+	}
+	return parts
+}
+
+// unquoteIdent removes quotes from identifiers, including each part
+// of qualified names. Dots within quotes are part of the name.
+func unquoteIdent(v string) string {
+	rs := []rune(v)
+	parts := make([]string, 0)
+	for i := 0; i < len(rs); {
+		end, err := wordEnd(rs, i)
+		if err != nil {
+			return v // tokenizeSQL reports unterminated quotes
+		}
+		p := string(rs[i:end])
+		if len(p) >= 2 && strings.ContainsAny(p[:1], "\"`[") {
+			p = p[1 : len(p)-1]
+		}
+		parts = append(parts, p)
+		i = end + 1 // skip the dot
+	}
+	return strings.Join(parts, ".")
+}
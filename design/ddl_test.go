@@ -0,0 +1,94 @@
+package design
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+)
+
+func TestParseDDL(t *testing.T) {
+	tables, err := ParseDDL(`
+/* a comment; with a semicolon */
+CREATE TABLE IF NOT EXISTS public.users (
+  id bigint PRIMARY KEY, -- comment
+  "e-mail" varchar(80) NOT NULL UNIQUE,
+  score numeric(10, 2) DEFAULT 0
+);
+CREATE TEMPORARY TABLE ` + "`post`" + ` (
+  id int AUTO_INCREMENT,
+  author bigint NOT NULL REFERENCES public.users(id),
+  slug text,
+  KEY author_idx (author),
+  UNIQUE KEY slug_uk (slug),
+  CONSTRAINT post_pk PRIMARY KEY (id)
+);
+CREATE INDEX x ON post (slug);
+ALTER TABLE ONLY post ADD CONSTRAINT fk FOREIGN KEY (slug) REFERENCES post (slug);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert := asserter.New(t)
+	assert().Equals(len(tables), 2)
+	users, post := tables[0], tables[1]
+	assert().Equals(users.Name, "public.users")
+	assert().Equals(len(users.Columns), 3)
+	assert().Equals(users.Columns[1].Name, "e-mail")
+	assert().Equals(users.Columns[1].Type, "varchar(80)")
+	assert().Equals(users.Columns[2].Type, "numeric(10,2)")
+	assert(users.Columns[1].NotNull).Error("e-mail may be null")
+	assert(users.IsUnique("e-mail")).Error("e-mail not unique")
+	assert(users.IsPrimary("id")).Error("id not primary")
+
+	assert().Equals(post.Name, "post")
+	assert().Equals(len(post.Columns), 3)
+	assert().Equals(post.PrimaryKey, []string{"id"})
+	assert().Equals(post.Unique, [][]string{{"slug"}})
+	assert().Equals(len(post.ForeignKeys), 2)
+	assert().Equals(post.ForeignKeys[0], &ForeignKey{
+		Columns:    []string{"author"},
+		RefTable:   "public.users",
+		RefColumns: []string{"id"},
+	})
+	assert(post.IsForeign("slug")).Error("slug not foreign")
+	assert(post.notNull([]string{"author"})).Error("author may be null")
+}
+
+func Test_unquoteIdent(t *testing.T) {
+	for v, exp := range map[string]string{
+		`public.users`:    "public.users",
+		`"my.table"`:      "my.table",
+		`"my.schema"."t"`: "my.schema.t",
+		"`db`.[x.y]":      "db.x.y",
+		`"unterminated.x`: `"unterminated.x`,
+		`public."e-mail"`: "public.e-mail",
+	} {
+		if got := unquoteIdent(v); got != exp {
+			t.Errorf("%s: got %q, expected %q", v, got, exp)
+		}
+	}
+}
+
+func TestParseDDL_errors(t *testing.T) {
+	for _, src := range []string{
+		"CREATE TABLE a (id int",
+		"CREATE TABLE (id int)",
+		"CREATE TABLE a id int",
+		"CREATE TABLE a (id int REFERENCES b)",
+		"CREATE TABLE a (b_id int, FOREIGN KEY (b_id) b (id))",
+		"CREATE TABLE a (name text DEFAULT 'x)",
+		"/* CREATE TABLE a (id int)",
+	} {
+		if _, err := ParseDDL(src); err == nil {
+			t.Errorf("expected error: %s", src)
+		}
+	}
+}
+
+func TestReadDDL(t *testing.T) {
+	tables, err := ReadDDL(strings.NewReader("CREATE TABLE a (id int);"))
+	if err != nil || len(tables) != 1 {
+		t.Error(tables, err)
+	}
+}
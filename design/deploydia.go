@@ -0,0 +1,288 @@
+package design
+
+import (
+	"io"
+
+	"github.com/gregoryv/draw"
+	"github.com/gregoryv/draw/shape"
+	"github.com/gregoryv/draw/xy"
+)
+
+// NewDeploymentDiagram returns an empty deployment diagram.
+func NewDeploymentDiagram() *DeploymentDiagram {
+	return &DeploymentDiagram{
+		Diagram: NewDiagram(),
+		root:    &Node{Group: shape.NewGroup("")},
+	}
+}
+
+// DeploymentDiagram shows where processes run, e.g. on hosts within
+// clusters and zones. Nodes size themselves to fit their children
+// and are laid out when the diagram is written.
+type DeploymentDiagram struct {
+	*Diagram
+
+	root        *Node
+	connections []*connection
+	placed      int // top level shapes added to the diagram
+}
+
+type connection struct {
+	from, to shape.Shape
+	txt      []string
+	arrow    *shape.Arrow
+}
+
+// Node adds a top level node, e.g. a host.
+func (d *DeploymentDiagram) Node(name string) *Node { return d.root.Node(name) }
+
+// Zone adds a top level zone, e.g. a data center or region.
+func (d *DeploymentDiagram) Zone(name string) *Node { return d.root.Zone(name) }
+
+// Cluster adds a top level cluster.
+func (d *DeploymentDiagram) Cluster(name string) *Node { return d.root.Cluster(name) }
+
+// Add adds top level shapes, e.g. shape.NewInternet().
+func (d *DeploymentDiagram) Add(s ...shape.Shape) { d.root.Add(s...) }
+
+// Vertical lays out top level nodes and shapes from top to bottom.
+func (d *DeploymentDiagram) Vertical() { d.root.Vertical = true }
+
+// Connect links two shapes, or nodes, with an arrow and optional
+// label. The arrow is drawn once all nodes are laid out, routed
+// around nodes and shapes not holding either end.
+func (d *DeploymentDiagram) Connect(from, to shape.Shape, txt ...string) {
+	d.connections = append(d.connections, &connection{
+		from: groupOf(from),
+		to:   groupOf(to),
+		txt:  txt,
+	})
+}
+
+// groupOf returns the group of nodes as they are found among the
+// children of their parent.
+func groupOf(s shape.Shape) shape.Shape {
+	if n, ok := s.(*Node); ok {
+		return n.Group
+	}
+	return s
+}
+
+// WriteSVG renders the diagram as SVG to the given writer.
+func (d *DeploymentDiagram) WriteSVG(w io.Writer) error {
+	d.root.SetFont(d.Font)
+	d.root.SetTextPad(d.TextPad)
+	d.root.Spacing = d.Spacing
+	d.root.SetX(d.Pad.Left - d.root.Pad.Left)
+	d.root.SetY(d.Pad.Top - d.root.Pad.Top)
+	children := d.root.Children()
+	for _, s := range children[d.placed:] {
+		d.Place(s)
+	}
+	d.placed = len(children)
+	for _, c := range d.connections {
+		if c.arrow == nil {
+			c.arrow, _ = d.Link(c.from, c.to, c.txt...)
+		}
+		d.route(c)
+	}
+	return d.Diagram.WriteSVG(w)
+}
+
+// route sets the points of the connection arrow. Arrows crossing
+// other nodes or shapes are routed around them with orthogonal
+// segments on the side giving the shortest path.
+func (d *DeploymentDiagram) route(c *connection) {
+	a := shape.NewArrowBetween(c.from, c.to)
+	c.arrow.Detach()
+	c.arrow.Start, c.arrow.End, c.arrow.Via = a.Start, a.End, nil
+	obstacles := d.obstacles(c)
+	if !crossesAny([]xy.Point{a.Start, a.End}, obstacles) {
+		return
+	}
+	var best []xy.Point
+	for _, path := range d.detours(c, obstacles) {
+		if crossesAny(path, obstacles) {
+			continue
+		}
+		if best == nil || pathLength(path) < pathLength(best) {
+			best = path
+		}
+	}
+	if best == nil {
+		return // no free path, leave it straight
+	}
+	c.arrow.Start, c.arrow.End = best[0], best[len(best)-1]
+	c.arrow.Via = best[1 : len(best)-1]
+}
+
+// obstacles returns the nodes and shapes that hold neither end of
+// the connection.
+func (d *DeploymentDiagram) obstacles(c *connection) []shape.Shape {
+	obstacles := make([]shape.Shape, 0)
+	// walk returns true if s is or holds an end of c
+	var walk func(s shape.Shape) bool
+	walk = func(s shape.Shape) bool {
+		if s == c.from || s == c.to {
+			return true
+		}
+		var holds bool
+		if g, ok := s.(*shape.Group); ok {
+			for _, child := range g.Children() {
+				if walk(child) {
+					holds = true
+				}
+			}
+		}
+		if !holds {
+			obstacles = append(obstacles, s)
+		}
+		return holds
+	}
+	for _, s := range d.root.Children() {
+		walk(s)
+	}
+	return obstacles
+}
+
+// detours returns paths leaving the ends of the connection on the
+// same side, below, right, above or left, passing outside the
+// obstacles in between.
+func (d *DeploymentDiagram) detours(c *connection, obstacles []shape.Shape) [][]xy.Point {
+	fx, fy := c.from.Position()
+	tx, ty := c.to.Position()
+	fw, fh := c.from.Width(), c.from.Height()
+	tw, th := c.to.Width(), c.to.Height()
+	fcx, fcy := fx+fw/2, fy+fh/2
+	tcx, tcy := tx+tw/2, ty+th/2
+	top, bottom := min(fy, ty), max(fy+fh, ty+th)
+	left, right := min(fx, tx), max(fx+fw, tx+tw)
+	for _, o := range obstacles {
+		ox, oy := o.Position()
+		if ox < max(fcx, tcx) && ox+o.Width() > min(fcx, tcx) {
+			top, bottom = min(top, oy), max(bottom, oy+o.Height())
+		}
+		if oy < max(fcy, tcy) && oy+o.Height() > min(fcy, tcy) {
+			left, right = min(left, ox), max(right, ox+o.Width())
+		}
+	}
+	gap := d.Spacing / 2
+	paths := [][]xy.Point{
+		{{X: fcx, Y: fy + fh}, {X: fcx, Y: bottom + gap}, {X: tcx, Y: bottom + gap}, {X: tcx, Y: ty + th}},
+		{{X: fx + fw, Y: fcy}, {X: right + gap, Y: fcy}, {X: right + gap, Y: tcy}, {X: tx + tw, Y: tcy}},
+	}
+	// don't leave the diagram
+	if top-gap > 0 {
+		paths = append(paths, []xy.Point{
+			{X: fcx, Y: fy}, {X: fcx, Y: top - gap}, {X: tcx, Y: top - gap}, {X: tcx, Y: ty},
+		})
+	}
+	if left-gap > 0 {
+		paths = append(paths, []xy.Point{
+			{X: fx, Y: fcy}, {X: left - gap, Y: fcy}, {X: left - gap, Y: tcy}, {X: tx, Y: tcy},
+		})
+	}
+	return paths
+}
+
+// crossesAny returns true if any segment of the path crosses the
+// inside of any of the shapes.
+func crossesAny(path []xy.Point, shapes []shape.Shape) bool {
+	for i := 1; i < len(path); i++ {
+		for _, s := range shapes {
+			if crosses(path[i-1], path[i], s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// crosses returns true if the segment p, q passes the inside of s,
+// touching the border is fine.
+func crosses(p, q xy.Point, s shape.Shape) bool {
+	x, y := s.Position()
+	x1, y1 := float64(x+1), float64(y+1)
+	x2, y2 := float64(x+s.Width()-1), float64(y+s.Height()-1)
+	px, py := float64(p.X), float64(p.Y)
+	dx, dy := float64(q.X-p.X), float64(q.Y-p.Y)
+	// clip the segment to the box, Liang-Barsky
+	t0, t1 := 0.0, 1.0
+	for _, e := range [][2]float64{
+		{-dx, px - x1}, {dx, x2 - px}, {-dy, py - y1}, {dy, y2 - py},
+	} {
+		switch {
+		case e[0] == 0:
+			if e[1] < 0 {
+				return false
+			}
+		case e[0] < 0:
+			if r := e[1] / e[0]; r > t1 {
+				return false
+			} else if r > t0 {
+				t0 = r
+			}
+		default:
+			if r := e[1] / e[0]; r < t0 {
+				return false
+			} else if r < t1 {
+				t1 = r
+			}
+		}
+	}
+	return true
+}
+
+// pathLength returns the sum of the lengths of the orthogonal
+// segments.
+func pathLength(path []xy.Point) int {
+	var n int
+	for i := 1; i < len(path); i++ {
+		n += abs(path[i].X-path[i-1].X) + abs(path[i].Y-path[i-1].Y)
+	}
+	return n
+}
+
+// SaveAs saves the diagram to filename as SVG
+func (d *DeploymentDiagram) SaveAs(filename string) error {
+	return saveAs(d, d.Diagram.Style, filename)
+}
+
+// Inline returns rendered SVG with inlined style
+func (d *DeploymentDiagram) Inline() string {
+	return draw.Inline(d, d.Diagram.Style)
+}
+
+// String returns rendered SVG
+func (d *DeploymentDiagram) String() string { return toString(d) }
+
+// Node is a group of shapes and other nodes, e.g. a host. The class
+// is given by the kind, ie. deploy-node, deploy-zone or
+// deploy-cluster.
+type Node struct {
+	*shape.Group
+}
+
+// Node adds a child node, e.g. a host.
+func (n *Node) Node(name string) *Node {
+	return n.add(name, "deploy-node")
+}
+
+// Zone adds a child zone.
+func (n *Node) Zone(name string) *Node {
+	return n.add(name, "deploy-zone")
+}
+
+// Cluster adds a child cluster.
+func (n *Node) Cluster(name string) *Node {
+	return n.add(name, "deploy-cluster")
+}
+
+func (n *Node) add(name, class string) *Node {
+	c := &Node{Group: shape.NewGroup(name)}
+	c.Font = n.Font
+	c.Pad = n.Pad
+	c.SetClass(class)
+	n.Group.Add(c.Group)
+	return c
+}
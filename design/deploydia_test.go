@@ -0,0 +1,127 @@
+package design
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/shape"
+	"github.com/gregoryv/draw/xy"
+)
+
+func TestDeploymentDiagram(t *testing.T) {
+	var (
+		d    = NewDeploymentDiagram()
+		zone = d.Zone("zone")
+		host = zone.Node("host")
+		app  = shape.NewRect("app")
+		db   = shape.NewDatabase("db")
+	)
+	host.Add(app)
+	zone.Add(db)
+	d.Connect(app, db, "SQL")
+	got := d.String()
+
+	assert := asserter.New(t)
+	assert().Contains(got, `class="deploy-zone"`)
+	assert().Contains(got, `class="deploy-node"`)
+	assert().Contains(got, "SQL")
+
+	// children are inside their parent
+	inside := func(parent, child shape.Shape) {
+		t.Helper()
+		px, py := parent.Position()
+		cx, cy := child.Position()
+		if cx <= px || cy <= py ||
+			cx+child.Width() >= px+parent.Width() ||
+			cy+child.Height() >= py+parent.Height() {
+			t.Errorf("%v not inside %v", child, parent)
+		}
+	}
+	inside(zone, host)
+	inside(host, app)
+	inside(zone, db)
+
+	// moving a node moves its children
+	ax, ay := app.Position()
+	shape.Move(zone, 10, 20)
+	bx, by := app.Position()
+	assert().Equals(bx-ax, 10)
+	assert().Equals(by-ay, 20)
+}
+
+func TestDeploymentDiagram_Connect(t *testing.T) {
+	var (
+		d      = NewDeploymentDiagram()
+		left   = d.Node("left")
+		middle = d.Node("middle")
+		right  = d.Node("right")
+		a      = shape.NewRect("a")
+		b      = shape.NewRect("b")
+	)
+	left.Add(a)
+	middle.Add(shape.NewRect("busy"))
+	right.Add(b)
+	d.Connect(a, b, "via")
+	d.Connect(left, middle)
+	got := d.String()
+
+	assert := asserter.New(t)
+	around, direct := d.connections[0].arrow, d.connections[1].arrow
+	assert(len(around.Via) > 0).Fatal("arrow not routed around middle")
+	path := append([]xy.Point{around.Start}, around.Via...)
+	path = append(path, around.End)
+	assert(!crossesAny(path, []shape.Shape{middle})).Errorf("%v crosses middle", path)
+	assert().Equals(len(direct.Via), 0)
+
+	// writing again neither duplicates nodes nor arrows
+	assert().Equals(d.String(), got)
+	assert().Equals(strings.Count(got, `class="deploy-node"`), 3)
+}
+
+func Test_crosses(t *testing.T) {
+	r := shape.NewRect("")
+	r.SetX(10)
+	r.SetY(10)
+	r.SetWidth(20)
+	r.SetHeight(20)
+	ok := func(p, q xy.Point, exp bool) {
+		t.Helper()
+		if got := crosses(p, q, r); got != exp {
+			t.Errorf("%v-%v: got %v, expected %v", p, q, got, exp)
+		}
+	}
+	ok(xy.Point{X: 0, Y: 20}, xy.Point{X: 40, Y: 20}, true)
+	ok(xy.Point{X: 0, Y: 0}, xy.Point{X: 40, Y: 40}, true)
+	ok(xy.Point{X: 0, Y: 10}, xy.Point{X: 40, Y: 10}, false) // border
+	ok(xy.Point{X: 0, Y: 0}, xy.Point{X: 5, Y: 40}, false)
+	ok(xy.Point{X: 0, Y: 20}, xy.Point{X: 8, Y: 20}, false)
+}
+
+func TestNode_Vertical(t *testing.T) {
+	n := &Node{Group: shape.NewGroup("")}
+	a, b := shape.NewRect("a"), shape.NewRect("b")
+	n.Add(a, b)
+	w, h := n.Width(), n.Height()
+	n.Vertical = true
+	assert := asserter.New(t)
+	assert(n.Width() < w).Error("vertical not narrower")
+	assert(n.Height() > h).Error("vertical not higher")
+}
+
+func TestDeploymentDiagram_Inline(t *testing.T) {
+	d := NewDeploymentDiagram()
+	d.Vertical()
+	net := shape.NewInternet()
+	d.Add(net)
+	c := d.Cluster("cluster")
+	n := c.Node("node")
+	cyl := shape.NewCylinder(20, 30)
+	n.Add(cyl)
+	d.Connect(net, cyl)
+	d.Connect(net, c)
+	got := d.Inline()
+	if strings.Contains(got, "class") {
+		t.Error("found class attributes\n", got)
+	}
+}
@@ -2,6 +2,7 @@ package design
 
 import (
 	"io"
+	"sort"
 
 	"github.com/gregoryv/draw"
 	"github.com/gregoryv/draw/shape"
@@ -25,6 +26,10 @@ type Diagram struct {
 
 	Caption *shape.Label
 	Legends map[string]string
+
+	// Markers draws heads and tails of all arrows, also those in
+	// groups, as SVG markers, see shape.Arrow.Markers.
+	Markers bool
 }
 
 // Place adds the shape to the diagram returning an adjuster for
@@ -84,28 +89,41 @@ func (d *Diagram) LinkAll(s ...shape.Shape) {
 	}
 }
 
-// Link places an arrow with a optional label above it between the two
-// shapes.
+// Link places an arrow with a optional label beside it between the
+// two shapes. The arrow and label follow the shapes if they are moved
+// before the diagram is written.
 func (d *Diagram) Link(from, to shape.Shape, txt ...string) (lnk *shape.Arrow, label *shape.Label) {
 	lnk = shape.NewArrowBetween(from, to)
 	d.Place(lnk)
 
 	if len(txt) > 0 {
-		label = shape.NewLabel(txt[0])
-
-		// find center of arrow
-		dir := lnk.Direction()
-		if dir == shape.DirectionLeft || dir == shape.DirectionRight {
-			d.Place(label).Above(lnk, label.Height()+label.Pad.Bottom)
-			d.VAlignCenter(lnk, label)
-		} else {
-			x, y := lnk.CenterPosition()
-			d.Place(label).At(x+label.Pad.Left, y-label.Font.Height)
-		}
+		label = lnk.AddLabel(txt[0], shape.LabelMiddle)
 	}
 	return
 }
 
+// placeLabels places the labels of all arrows so they avoid other
+// shapes and labels.
+func (d *Diagram) placeLabels() {
+	obstacles := make([]shape.Shape, 0, len(d.Content))
+	arrows := make([]*shape.Arrow, 0)
+	for _, s := range d.Content {
+		switch s := s.(type) {
+		case *shape.Arrow:
+			arrows = append(arrows, s)
+		case *shape.Line:
+		case shape.Shape:
+			obstacles = append(obstacles, s)
+		}
+	}
+	for _, a := range arrows {
+		a.PlaceLabels(obstacles...)
+		for _, l := range a.Labels() {
+			obstacles = append(obstacles, l)
+		}
+	}
+}
+
 func (d *Diagram) applyStyle(s interface{}) {
 	if s, ok := s.(shape.HasFont); ok {
 		s.SetFont(d.Font)
@@ -128,10 +146,47 @@ func (d *Diagram) Inline() string {
 // String returns rendered SVG
 func (d *Diagram) String() string { return toString(d) }
 
+// adaptTo grows the diagram to include x, y.
+func (d *Diagram) adaptTo(x, y int) {
+	if x > d.Width() {
+		d.SetWidth(x)
+	}
+	if y > d.Height() {
+		d.SetHeight(y)
+	}
+}
+
+// setMarkers draws heads and tails of arrows as markers, including
+// arrows within groups.
+func setMarkers(s draw.SVGWriter) {
+	switch s := s.(type) {
+	case *shape.Arrow:
+		s.Markers = true
+	case interface{ Children() []shape.Shape }:
+		for _, c := range s.Children() {
+			setMarkers(c)
+		}
+	}
+}
+
 func (d *Diagram) WriteSVG(w io.Writer) error {
+	d.placeLabels()
+	if d.Markers {
+		for _, s := range d.Content {
+			setMarkers(s)
+		}
+	}
 	if d.Width() == 0 && d.Height() == 0 {
 		d.AdaptSize()
 	}
+	// caption and legends are placed below the content on each
+	// write, so it can be written many times
+	content, width, height := d.Content, d.Width(), d.Height()
+	defer func() {
+		d.Content = content
+		d.SetWidth(width)
+		d.SetHeight(height)
+	}()
 	margin := 10
 	if d.Caption != nil {
 		x := (d.Width() - d.Caption.Width()) / 2
@@ -145,7 +200,13 @@ func (d *Diagram) WriteSVG(w io.Writer) error {
 	if len(d.Legends) > 0 {
 		x := 8
 		y := d.Height() + margin
-		for class, caption := range d.Legends {
+		classes := make([]string, 0, len(d.Legends))
+		for class := range d.Legends {
+			classes = append(classes, class)
+		}
+		sort.Strings(classes)
+		for _, class := range classes {
+			caption := d.Legends[class]
 			legendSymbol := shape.NewRect("")
 			legendSymbol.SetClass(class)
 			legendSymbol.SetHeight(10)
@@ -158,7 +219,7 @@ func (d *Diagram) WriteSVG(w io.Writer) error {
 			y += 20
 		}
 		d.AdaptSize()
-		d.SetHeight(d.Height() + d.Caption.Font.Height/2)
+		d.SetHeight(d.Height() + d.Font.Height/2)
 	}
 	return d.SVG.WriteSVG(w)
 }
@@ -177,17 +238,15 @@ func (d *Diagram) AdaptSize() (int, int) {
 			x = min(s.Start.X, s.End.X)
 			y = min(s.Start.Y, s.End.Y)
 		case *shape.Arrow:
-			x = min(s.Start.X, s.End.X)
-			y = min(s.Start.Y, s.End.Y)
-		}
-		w := x + s.Width()
-		if w > d.Width() {
-			d.SetWidth(w)
-		}
-		h := y + s.Height()
-		if h > d.Height() {
-			d.SetHeight(h)
+			_, max := s.Bounds()
+			d.adaptTo(max.X, max.Y)
+			for _, l := range s.Labels() {
+				x, y := l.Position()
+				d.adaptTo(x+l.Width(), y+l.Height())
+			}
+			continue
 		}
+		d.adaptTo(x+s.Width(), y+s.Height())
 	}
 	d.SetWidth(d.Width() + 1)   // Fixes right most pixels not visible
 	d.SetHeight(d.Height() + 1) // Fixes bottom pixels not visible
@@ -201,6 +260,20 @@ func min(a, b int) int {
 	return b
 }
 
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
 // SetCaption adds a caption to the bottom of the diagram.
 func (d *Diagram) SetCaption(txt string) {
 	l := shape.NewLabel(txt)
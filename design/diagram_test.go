@@ -3,6 +3,7 @@ package design
 import (
 	"bytes"
 	"io"
+	"io/ioutil"
 	"strings"
 	"testing"
 
@@ -78,6 +79,31 @@ func TestDiagram(t *testing.T) {
 			t.Error("found class attributes\n", got)
 		}
 	})
+
+	t.Run("Links follow shapes moved after linking", func(t *testing.T) {
+		var (
+			d    = NewDiagram()
+			a    = shape.NewRect("a")
+			b    = shape.NewRect("b")
+			late = NewDiagram()
+			c    = shape.NewRect("a")
+			e    = shape.NewRect("b")
+		)
+		d.Place(a).At(10, 10)
+		d.Place(b).At(10, 10)
+		lnk, label := d.Link(a, b, "uses")
+		shape.NewAdjuster(b).RightOf(a, 100)
+
+		late.Place(c).At(10, 10)
+		late.Place(e).RightOf(c, 100)
+		late.Link(c, e, "uses")
+
+		assert := asserter.New(t)
+		assert().Equals(d.String(), late.String())
+		lx, _ := label.Position()
+		ax, _ := lnk.Position()
+		assert(lx > ax).Errorf("label not along arrow: %v, %v", lx, ax)
+	})
 }
 
 type dummy struct{}
@@ -86,3 +112,18 @@ func (d *dummy) WriteSVG(w io.Writer) error {
 	_, err := w.Write([]byte("..."))
 	return err
 }
+
+func TestDiagram_Markers(t *testing.T) {
+	d := NewDiagram()
+	d.Markers = true
+	g := shape.NewGroup("g")
+	inner := shape.NewArrow(0, 0, 50, 0)
+	g.Add(inner)
+	outer := shape.NewArrow(0, 40, 50, 40)
+	d.Place(g, outer)
+	d.WriteSVG(ioutil.Discard)
+
+	assert := asserter.New(t)
+	assert(outer.Markers).Error("arrow not drawn with markers")
+	assert(inner.Markers).Error("arrow in group not drawn with markers")
+}
@@ -0,0 +1,278 @@
+package design
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gregoryv/draw"
+	"github.com/gregoryv/draw/shape"
+	"github.com/gregoryv/draw/xy"
+)
+
+// NewERDiagram returns an empty entity relationship diagram.
+func NewERDiagram() *ERDiagram {
+	return &ERDiagram{
+		Diagram: NewDiagram(),
+		tables:  make([]*Table, 0),
+	}
+}
+
+// ERDiagram shows database tables with their columns and foreign
+// keys in crow's foot notation. Tables are placed in rows with
+// referenced tables above those referencing them.
+type ERDiagram struct {
+	*Diagram
+
+	tables []*Table
+}
+
+// ParseERDiagram returns a diagram of the tables in the given SQL,
+// see ReadDDL.
+func ParseERDiagram(r io.Reader) (*ERDiagram, error) {
+	tables, err := ReadDDL(r)
+	if err != nil {
+		return nil, err
+	}
+	d := NewERDiagram()
+	d.Add(tables...)
+	return d, nil
+}
+
+// LoadERDiagram returns a diagram of the tables in the given SQL
+// file.
+func LoadERDiagram(filename string) (*ERDiagram, error) {
+	fh, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	return ParseERDiagram(fh)
+}
+
+// Add adds tables to the diagram.
+func (d *ERDiagram) Add(tables ...*Table) {
+	d.tables = append(d.tables, tables...)
+}
+
+// Tables returns all tables in the diagram.
+func (d *ERDiagram) Tables() []*Table { return d.tables }
+
+// WriteSVG renders the diagram as SVG to the given writer.
+func (d *ERDiagram) WriteSVG(w io.Writer) error {
+	records := make(map[*Table]*shape.Record)
+	for _, t := range d.tables {
+		records[t] = newTableRecord(t)
+		d.applyStyle(records[t])
+	}
+	y := d.Pad.Top
+	rows := d.layers()
+	widths := make([]int, len(rows))
+	var widest int
+	for i, row := range rows {
+		for _, t := range row {
+			widths[i] += d.tableWidth(t, records[t])
+		}
+		widths[i] -= d.Spacing
+		if widths[i] > widest {
+			widest = widths[i]
+		}
+	}
+	for i, row := range rows {
+		x := d.Pad.Left + (widest-widths[i])/2
+		var h int
+		for _, t := range row {
+			r := records[t]
+			d.Place(r).At(x, y)
+			x += d.tableWidth(t, r)
+			if r.Height() > h {
+				h = r.Height()
+			}
+		}
+		y += h + 2*d.Spacing
+	}
+	for _, t := range d.tables {
+		for _, fk := range t.ForeignKeys {
+			ref := d.table(fk.RefTable)
+			if ref == nil {
+				continue
+			}
+			var a *shape.Arrow
+			if ref == t {
+				a = selfReference(records[t])
+			} else {
+				a = shape.NewArrowBetween(records[t], records[ref])
+			}
+			a.SetClass("er-arrow")
+			a.Tail = shape.NewCrowFoot(childCardinality(t, fk))
+			a.Head = shape.NewCrowFoot(parentCardinality(t, fk))
+			d.Prepend(a)
+		}
+	}
+	return d.Diagram.WriteSVG(w)
+}
+
+// newTableRecord returns a record with one field per column, keys
+// are marked with PK, FK and UK.
+func newTableRecord(t *Table) *shape.Record {
+	r := shape.NewRecord(t.Name)
+	for _, c := range t.Columns {
+		keys := make([]string, 0, 3)
+		if t.IsPrimary(c.Name) {
+			keys = append(keys, "PK")
+		}
+		if t.IsForeign(c.Name) {
+			keys = append(keys, "FK")
+		}
+		if t.IsUnique(c.Name) {
+			keys = append(keys, "UK")
+		}
+		field := c.Name + " " + c.Type
+		if len(keys) > 0 {
+			field = strings.Join(keys, ",") + " " + field
+		}
+		r.Fields = append(r.Fields, field)
+	}
+	return r
+}
+
+// tableWidth returns the width of the record including spacing to
+// the next table and room for a self reference.
+func (d *ERDiagram) tableWidth(t *Table, r *shape.Record) int {
+	w := r.Width() + d.Spacing
+	if t.references(t.Name) {
+		w += selfLoop
+	}
+	return w
+}
+
+const selfLoop = 24 // width of self references
+
+// selfReference returns an arrow looping on the right side of r.
+func selfReference(r *shape.Record) *shape.Arrow {
+	x := r.X + r.Width()
+	y1, y2 := r.Y+r.Height()/3, r.Y+2*r.Height()/3
+	a := shape.NewArrow(x, y1, x, y2)
+	a.Via = []xy.Point{{X: x + selfLoop, Y: y1}, {X: x + selfLoop, Y: y2}}
+	return a
+}
+
+// childCardinality returns the cardinality at the referencing end of
+// the foreign key.
+func childCardinality(t *Table, fk *ForeignKey) shape.Cardinality {
+	if t.isKey(fk.Columns) {
+		return shape.ZeroOrOne
+	}
+	return shape.ZeroOrMany
+}
+
+// parentCardinality returns the cardinality at the referenced end of
+// the foreign key.
+func parentCardinality(t *Table, fk *ForeignKey) shape.Cardinality {
+	if t.notNull(fk.Columns) {
+		return shape.ExactlyOne
+	}
+	return shape.ZeroOrOne
+}
+
+func (d *ERDiagram) table(name string) *Table {
+	for _, t := range d.tables {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// layers returns tables in rows, each table one row below the
+// lowest table it references. Tables in a row are ordered by the
+// position of the tables they reference.
+func (d *ERDiagram) layers() [][]*Table {
+	level := make(map[*Table]int)
+	var find func(t *Table, visiting map[*Table]bool) int
+	find = func(t *Table, visiting map[*Table]bool) int {
+		if l, found := level[t]; found {
+			return l
+		}
+		visiting[t] = true
+		var l int
+		for _, fk := range t.ForeignKeys {
+			ref := d.table(fk.RefTable)
+			if ref == nil || visiting[ref] {
+				continue // unknown, self or cyclic reference
+			}
+			if rl := find(ref, visiting) + 1; rl > l {
+				l = rl
+			}
+		}
+		delete(visiting, t)
+		level[t] = l
+		return l
+	}
+	for _, t := range d.tables {
+		find(t, make(map[*Table]bool))
+	}
+	// move referenced tables down, next to the tables referencing
+	// them
+	byLevel := append([]*Table{}, d.tables...)
+	sort.SliceStable(byLevel, func(i, j int) bool {
+		return level[byLevel[i]] > level[byLevel[j]]
+	})
+	for _, t := range byLevel {
+		lowest := -1
+		for _, c := range d.tables {
+			if c != t && c.references(t.Name) && (lowest == -1 || level[c] < lowest) {
+				lowest = level[c]
+			}
+		}
+		if lowest-1 > level[t] {
+			level[t] = lowest - 1
+		}
+	}
+	rows := make([][]*Table, 0)
+	for _, t := range d.tables {
+		l := level[t]
+		for len(rows) <= l {
+			rows = append(rows, make([]*Table, 0))
+		}
+		rows[l] = append(rows[l], t)
+	}
+	pos := make(map[*Table]float64)
+	for _, row := range rows {
+		center := func(t *Table) float64 {
+			var sum float64
+			var n int
+			for _, fk := range t.ForeignKeys {
+				if p, found := pos[d.table(fk.RefTable)]; found {
+					sum += p
+					n++
+				}
+			}
+			if n == 0 {
+				return 0
+			}
+			return sum / float64(n)
+		}
+		sort.SliceStable(row, func(i, j int) bool {
+			return center(row[i]) < center(row[j])
+		})
+		for i, t := range row {
+			pos[t] = float64(i) / float64(len(row))
+		}
+	}
+	return rows
+}
+
+// SaveAs saves the diagram to filename as SVG
+func (d *ERDiagram) SaveAs(filename string) error {
+	return saveAs(d, d.Diagram.Style, filename)
+}
+
+// Inline returns rendered SVG with inlined style
+func (d *ERDiagram) Inline() string {
+	return draw.Inline(d, d.Diagram.Style)
+}
+
+// String returns rendered SVG
+func (d *ERDiagram) String() string { return toString(d) }
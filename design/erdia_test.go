@@ -0,0 +1,67 @@
+package design
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/shape"
+)
+
+func TestERDiagram(t *testing.T) {
+	d, err := LoadERDiagram("testdata/shop.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert := asserter.New(t)
+	assert().Equals(len(d.Tables()), 5)
+
+	var names [][]string
+	for _, row := range d.layers() {
+		var r []string
+		for _, t := range row {
+			r = append(r, t.Name)
+		}
+		names = append(names, r)
+	}
+	assert().Equals(names, [][]string{
+		{"customer"},
+		{"address", "product", "order"},
+		{"order_line"},
+	})
+
+	address := d.table("address")
+	fk := address.ForeignKeys[0]
+	assert().Equals(childCardinality(address, fk), shape.ZeroOrOne)
+	assert().Equals(parentCardinality(address, fk), shape.ExactlyOne)
+	product := d.table("product")
+	fk = product.ForeignKeys[0]
+	assert().Equals(childCardinality(product, fk), shape.ZeroOrMany)
+	assert().Equals(parentCardinality(product, fk), shape.ZeroOrOne)
+
+	got := d.String()
+	assert().Contains(got, "PK,FK order_id integer")
+	assert().Contains(got, `class="er-arrow"`)
+}
+
+func TestERDiagram_Inline(t *testing.T) {
+	d, err := ParseERDiagram(strings.NewReader(`
+CREATE TABLE a (id int PRIMARY KEY, parent int REFERENCES a);
+CREATE TABLE b (a_id int NOT NULL REFERENCES a (id));`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := d.Inline()
+	if strings.Contains(got, "class") {
+		t.Error("found class attributes\n", got)
+	}
+}
+
+func TestLoadERDiagram_missing(t *testing.T) {
+	if _, err := LoadERDiagram("testdata/nosuch.sql"); err == nil {
+		t.Error("expected error")
+	}
+	if _, err := ParseERDiagram(strings.NewReader("CREATE TABLE")); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 func TestExample(t *testing.T) {
 	ExampleSequenceDiagram()
+	ExampleNewCallSequence()
 }
 
 func ExampleSequenceDiagram() {
@@ -30,3 +31,11 @@ func ExampleSequenceDiagram() {
 	d.Link(srv, cli, "Send HTML")
 	d.SaveAs("img/app_sequence_diagram.svg")
 }
+
+func ExampleNewCallSequence() {
+	d, err := design.NewCallSequence("testdata/shop", "Run", 4)
+	if err != nil {
+		panic(err)
+	}
+	d.SaveAs("img/call_sequence.svg")
+}
@@ -1,6 +1,7 @@
 package design_test
 
 import (
+	"log"
 	"testing"
 
 	"github.com/gregoryv/draw"
@@ -183,6 +184,50 @@ func ExampleActivityDiagram() {
 	d.SaveAs("img/activity_diagram.svg")
 }
 
+func ExampleActivityDiagram_lanes() {
+	var (
+		d   = design.NewActivityDiagram()
+		dev = d.Lane("Developer")
+	)
+	d.Start().At(20, 20)
+	d.Then("Push commit")
+	ci := d.Lane("CI")
+	fork := d.Fork()
+	d.Branch(fork, "Build")
+	d.Then("Package")
+	pkg := d.Last()
+	d.Branch(fork, "Test")
+	test := d.Last()
+	d.Join(pkg, test)
+	dec := d.Decide()
+	d.Then("Publish", "ok")
+	publish := d.Last()
+	d.InLane(dev)
+	fix := shape.NewState("Fix")
+	d.If(dec, "failed", fix)
+	d.InLane(ci)
+	d.Merge(publish, fix)
+	d.Exit()
+	d.SaveAs("img/activity_lanes.svg")
+}
+
+func ExampleActivityDiagram_loops() {
+	var (
+		d = design.NewActivityDiagram()
+	)
+	d.Start().At(80, 20)
+	d.Then("Read line")
+	read := d.Last()
+	d.Then("Parse")
+	d.Until(read, "more lines")
+	loop := d.While()
+	d.Then("Send request", "pending")
+	d.Then("Wait")
+	d.EndWhile(loop, "done")
+	d.Exit()
+	d.SaveAs("img/activity_loops.svg")
+}
+
 func ExampleGanttChart() {
 	var (
 		d   = design.NewGanttChart("20191111", 30)
@@ -214,11 +259,473 @@ func ExampleGanttChart_year() {
 	d.SaveAs("img/gantt_year.svg")
 }
 
+func ExampleGanttChart_dependencies() {
+	var (
+		d     = design.NewGanttChart("20191111", 30)
+		spec  = d.Add("Design").Progress(100)
+		build = d.Add("Build").Progress(40)
+		docs  = d.Add("Document").Yellow()
+		dev   = d.Group("Develop", spec, build, docs)
+		test  = d.Add("Test").Orange()
+		done  = d.Milestone("Release")
+	)
+	d.MarkDate("20191118")
+	d.Place(spec).At("20191111", 4)
+	d.Place(build).After(spec, 6)
+	d.Place(docs).Depend(build, design.StartToStart, 4)
+	d.Place(test).Depend(build, design.FinishToFinish, 3)
+	d.Place(done).After(dev, 0)
+	d.ShowCritical = true
+	d.SetCaption("Figure 1. Grouped tasks with dependencies")
+	d.SaveAs("img/gantt_dependencies.svg")
+}
+
+func ExampleGanttChart_calendar() {
+	var (
+		d    = design.NewGanttChart("20191216", 21)
+		dev  = d.Add("Develop")
+		test = d.Add("Test").Orange()
+		rel  = d.Milestone("Release")
+	)
+	d.Calendar = design.NewCalendar()
+	if err := d.Calendar.LoadHolidays("testdata/holidays.ics"); err != nil {
+		panic(err)
+	}
+	d.MarkDate("20191216")
+	d.Place(dev).At("20191216", 5)
+	d.Place(test).After(dev, 4)
+	d.Place(rel).After(test, 0)
+	d.SetCaption("Figure 1. Durations in working days")
+	d.SaveAs("img/gantt_calendar.svg")
+}
+
+func ExampleGanttChart_months() {
+	var (
+		d    = design.NewGanttChart("20200101", 366)
+		plan = d.Add("Plan")
+		dev  = d.Add("Develop")
+		rel  = d.Milestone("Release")
+	)
+	d.Scale = design.ScaleMonths
+	d.MarkDate("20200415")
+	d.Place(plan).At("20200101", 45)
+	d.Place(dev).After(plan, 180)
+	d.Place(rel).After(dev, 0)
+	d.SetCaption("Figure 1. Roadmap in months")
+	d.SaveAs("img/gantt_months.svg")
+}
+
+func ExampleGanttChart_resources() {
+	var (
+		d    = design.NewGanttChart("20191111", 21)
+		spec = d.Add("Design").Assign("Anna")
+		code = d.Add("Build").Assign("John")
+		docs = d.Add("Document").Assign("Anna").Blue()
+		test = d.Add("Test").Assign("Anna", "John").Yellow()
+	)
+	d.Calendar = design.NewCalendar()
+	d.GroupByResource = true
+	d.ShowUtilisation = true
+	d.Place(spec).At("20191111", 4)
+	d.Place(code).After(spec, 6)
+	d.Place(docs).Depend(spec, design.StartToStart, 6)
+	d.Place(test).After(code, 3)
+	d.SetCaption("Figure 1. Tasks per resource")
+	d.SaveAs("img/gantt_resources.svg")
+}
+
+func ExampleLoadGanttChart() {
+	d, err := design.LoadGanttChart("testdata/project.csv")
+	if err != nil {
+		panic(err)
+	}
+	d.SetCaption("Figure 1. Project loaded from CSV")
+	d.SaveAs("img/gantt_csv.svg")
+}
+
+func ExampleLoadERDiagram() {
+	d, err := design.LoadERDiagram("testdata/shop.sql")
+	if err != nil {
+		panic(err)
+	}
+	d.SetCaption("Figure 1. Web shop database")
+	d.SaveAs("img/er_diagram.svg")
+}
+
+func ExampleC4Diagram() {
+	var (
+		d        = design.NewC4Diagram()
+		customer = design.NewC4Person("Customer", "A customer of the bank with a personal account")
+		bank     = design.NewC4SoftwareSystem("Internet Banking", "Lets customers view their accounts and make payments")
+		mail     = design.NewC4SoftwareSystem("E-mail", "Microsoft Exchange e-mail system")
+	)
+	mail.External = true
+	d.Place(customer).At(20, 20)
+	d.Place(bank).Below(customer, 80)
+	d.Place(mail).RightOf(bank, 120)
+	d.Rel(customer, bank, "Views balances", "HTTPS")
+	d.Rel(bank, mail, "Sends e-mail", "SMTP")
+	d.SetCaption("Figure 1. System context")
+	d.SaveAs("img/c4_context.svg")
+}
+
+func ExampleC4Diagram_containers() {
+	var (
+		d        = design.NewC4Diagram()
+		customer = design.NewC4Person("Customer", "A customer of the bank")
+		web      = design.NewC4Container("Web App", "Go", "Delivers the single page app and API")
+		spa      = design.NewC4Container("Single-Page App", "JavaScript", "Banking functions in the browser")
+		db       = design.NewC4Container("Database", "PostgreSQL", "Stores accounts and transactions")
+		mail     = design.NewC4SoftwareSystem("E-mail", "Microsoft Exchange")
+	)
+	mail.External = true
+	d.Place(customer).At(40, 20)
+	d.Place(spa).Below(customer, 90)
+	d.Place(web).RightOf(spa, 110)
+	d.Place(db).Below(spa, 90)
+	d.Place(mail).RightOf(web, 110)
+	d.Boundary("Internet Banking", "Software System", spa, web, db)
+	d.Rel(customer, spa, "Uses", "HTTPS")
+	d.Rel(spa, web, "Calls API", "JSON/HTTPS")
+	d.Rel(web, db, "Reads and writes", "SQL/TCP")
+	d.Rel(web, mail, "Sends e-mail", "SMTP")
+	d.SetCaption("Figure 1. Containers of Internet Banking")
+	d.SaveAs("img/c4_containers.svg")
+}
+
+func ExampleDeploymentDiagram() {
+	d := design.NewDeploymentDiagram()
+	net := shape.NewInternet()
+	d.Add(net)
+	zone := d.Zone("eu-north-1")
+	lb := shape.NewComponent("Load balancer")
+	zone.Add(lb)
+	k8s := zone.Cluster("Kubernetes")
+	k8s.Vertical = true
+	api1 := shape.NewComponent("api")
+	k8s.Node("node-1").Add(api1)
+	api2 := shape.NewComponent("api")
+	k8s.Node("node-2").Add(api2)
+	db := shape.NewDatabase("orders")
+	zone.Add(db)
+
+	d.Connect(net, lb)
+	d.Connect(lb, api1)
+	d.Connect(lb, api2)
+	d.Connect(api1, db)
+	d.Connect(api2, db)
+	d.SetCaption("Figure 1. Deployment of the order service")
+	d.SaveAs("img/deployment_diagram.svg")
+}
+
+func ExampleDiagram_group() {
+	var (
+		d        = design.NewDiagram()
+		client   = shape.NewActor()
+		payments = shape.NewGroup("payments service")
+		api      = shape.NewComponent("api")
+		ledger   = shape.NewComponent("ledger")
+		db       = shape.NewDatabase("payments")
+	)
+	payments.Add(api, ledger, db)
+	d.Place(client).At(20, 20)
+	d.Place(payments).RightOf(client, 80)
+	d.HAlignCenter(api, client)
+	d.Link(client, api)
+	d.Link(api, ledger)
+	d.Link(ledger, db)
+	d.SaveAs("img/diagram_group.svg")
+}
+
+func ExampleDiagram_anchors() {
+	var (
+		d       = design.NewDiagram()
+		gateway = shape.NewComponent("gateway")
+		orders  = shape.NewComponent("orders")
+		users   = shape.NewComponent("users")
+		logs    = shape.NewDatabase("logs")
+	)
+	gateway.SetPort("orders", shape.Anchor{X: 1, Y: 0.25})
+	gateway.SetPort("users", shape.Anchor{X: 1, Y: 0.75})
+	d.Place(gateway).At(20, 60)
+	d.Place(orders).At(200, 20)
+	d.Place(users).Below(orders, 60)
+	d.Place(logs).Below(gateway, 60)
+	d.VAlignCenter(gateway, logs)
+	d.Place(shape.NewPortArrow(gateway, "orders", orders, ""))
+	d.Place(shape.NewPortArrow(gateway, "users", users, ""))
+	d.Place(shape.NewAnchoredArrow(gateway, shape.South, logs, shape.North))
+	d.Place(shape.NewAnchoredArrow(users, shape.South, logs, shape.East))
+
+	// arrows follow shapes moved after they are placed
+	shape.Move(logs, 0, 20)
+	d.SaveAs("img/diagram_anchors.svg")
+}
+
+func ExampleDiagram_linkFirst() {
+	var (
+		d      = design.NewDiagram()
+		client = shape.NewComponent("client")
+		server = shape.NewComponent("server")
+		db     = shape.NewDatabase("db")
+	)
+	// link before the shapes are placed
+	d.Link(client, server, "request")
+	d.Link(server, db, "query")
+	d.Place(client).At(20, 40)
+	d.Place(server).RightOf(client, 100)
+	d.Place(db).Below(server, 60)
+	d.VAlignCenter(server, db)
+	d.SaveAs("img/diagram_link_first.svg")
+}
+
+func ExampleDiagram_arrowLabels() {
+	var (
+		d       = design.NewDiagram()
+		order   = shape.NewRecord("Order")
+		line    = shape.NewRecord("OrderLine")
+		product = shape.NewRecord("Product")
+	)
+	order.Fields = []string{"id", "created"}
+	line.Fields = []string{"quantity"}
+	product.Fields = []string{"sku", "name"}
+	d.Place(order).At(20, 20)
+	d.Place(line).RightOf(order, 160)
+	d.Place(product).Below(line, 80)
+	d.VAlignCenter(line, product)
+
+	lines, _ := d.Link(order, line, "contains")
+	lines.AddLabel("1", shape.LabelStart)
+	lines.AddLabel("1..*", shape.LabelEnd)
+	refs, _ := d.Link(line, product, "refers to")
+	refs.AddLabel("0..*", shape.LabelStart)
+	refs.AddLabel("1", shape.LabelEnd)
+	d.Link(order, product, "lists")
+	d.SaveAs("img/diagram_arrow_labels.svg")
+}
+
+func ExampleDiagram_uml() {
+	var (
+		d        = design.NewDiagram()
+		shop     = shape.NewPackage("shop")
+		orders   = shape.NewComponent("orders")
+		provided = shape.NewLollipop("Orders")
+		checkout = shape.NewComponent("checkout")
+		required = shape.NewSocket("Payments")
+		server   = shape.NewNode("app server")
+		jar      = shape.NewArtifact("shop.jar")
+		customer = shape.NewActor()
+		buy      = shape.NewUseCase("Place\norder")
+	)
+	d.Place(shop).At(20, 20)
+	d.Place(orders).RightOf(shop, 60)
+	d.Place(checkout).Below(orders, 30)
+	// interfaces stick out from the middle of the right side
+	for _, c := range []struct {
+		shape.Shape
+		i *shape.Interface
+	}{{orders, provided}, {checkout, required}} {
+		d.Place(c.i).RightOf(c, 0)
+		d.HAlignTop(c, c.i)
+		shape.Move(c.i, 0, c.Height()/2-c.i.Radius)
+	}
+	d.Place(server).Below(shop, 80)
+	d.Place(jar).RightOf(server, 60)
+	d.HAlignBottom(server, jar)
+	d.Link(jar, server, "deployed on")
+	d.Place(customer).Below(server, 40)
+	d.Place(buy).RightOf(customer, 80)
+	d.HAlignCenter(customer, buy)
+	d.Link(customer, buy)
+	d.SaveAs("img/diagram_uml.svg")
+}
+
+func ExampleDiagram_decoration() {
+	var (
+		d       = design.NewDiagram()
+		rounded = shape.NewRect("rounded")
+		dashed  = shape.NewComponent("dashed")
+		faded   = shape.NewUseCase("faded")
+		shadow  = shape.NewDatabase("shadow")
+	)
+	rounded.CornerRadius = 8
+	dashed.Dash = "5,3"
+	faded.Opacity = 0.4
+	shadow.Shadow = true
+	d.Place(rounded).At(20, 20)
+	d.Place(dashed).RightOf(rounded)
+	d.Place(faded).RightOf(dashed)
+	d.Place(shadow).RightOf(faded)
+	d.HAlignCenter(rounded, dashed, faded, shadow)
+	lnk, _ := d.Link(faded, shadow)
+	lnk.Decorate(shape.Decoration{Dash: "2,2", Opacity: 0.6})
+	d.SaveAs("img/diagram_decoration.svg")
+}
+
+func ExampleDiagram_fills() {
+	var (
+		d      = design.NewDiagram()
+		cloud  = shape.NewRect("cloud")
+		legacy = shape.NewComponent("legacy")
+		core   = shape.NewUseCase("core")
+	)
+	// classes refer to definitions added to the diagram
+	sky := draw.NewLinearGradient("sky", "#ffffff", "#99e6ff")
+	sky.Angle = 90
+	d.Define(sky)
+	d.SetClassAttributes("sky", `stroke="#d3d3d3" fill="url(#sky)"`)
+	d.SetClassAttributes("sky-title", `font-family="Arial,Helvetica,sans-serif"`)
+	cloud.SetClass("sky")
+
+	// shapes refer to definitions directly
+	legacy.Fill = draw.NewHatch("out-of-scope", "#d3d3d3")
+	core.Fill = draw.NewRadialGradient("glow", "#fdfd96", "#ffffff")
+
+	d.Place(cloud).At(20, 20)
+	d.Place(legacy).RightOf(cloud)
+	d.Place(core).RightOf(legacy)
+	d.HAlignCenter(cloud, legacy, core)
+	d.SaveAs("img/diagram_fills.svg")
+}
+
+func ExampleDiagram_markers() {
+	var (
+		d      = design.NewDiagram()
+		hub    = shape.NewComponent("hub")
+		north  = shape.NewRect("north")
+		east   = shape.NewRect("east")
+		south  = shape.NewRect("south")
+		part   = shape.NewRecord("part")
+		remote = shape.NewRect("remote")
+	)
+	// heads and tails follow the exact angle of each arrow
+	d.Markers = true
+	d.Place(hub).At(120, 80)
+	d.Place(north).At(20, 20)
+	d.Place(east).At(260, 40)
+	d.Place(south).At(60, 170)
+	d.Place(part).At(240, 150)
+	d.Place(remote).At(30, 100)
+	d.Link(hub, north)
+	d.Link(hub, east)
+	d.Link(hub, south)
+	d.Link(hub, remote)
+	compose := shape.NewArrowBetween(hub, part)
+	compose.SetClass("compose-arrow")
+	compose.Tail = shape.NewDiamond()
+	d.Place(compose)
+	d.SaveAs("img/diagram_markers.svg")
+}
+
+func ExampleDiagram_images() {
+	var (
+		d       = design.NewDiagram()
+		user    = shape.NewActor()
+		service = shape.NewComponent("storage")
+		cdn     = shape.NewRect("cdn")
+	)
+	load := func() *shape.Image {
+		img, err := shape.LoadImage("testdata/cloud.svg")
+		if err != nil {
+			log.Fatal(err)
+		}
+		return img
+	}
+	logo := load()
+	// icons are drawn left of the title
+	service.Icon = load()
+	d.Place(user).At(20, 30)
+	d.Place(logo).RightOf(user, 80)
+	d.Place(service).RightOf(logo, 80)
+	d.Place(cdn).Below(service, 40)
+	d.HAlignCenter(user, logo, service)
+	d.VAlignCenter(service, cdn)
+	d.Link(user, logo)
+	d.Link(logo, service)
+	d.Link(service, cdn)
+	d.SaveAs("img/diagram_images.svg")
+}
+
+func ExamplePackageDiagram() {
+	d := design.NewPackageDiagram()
+	if err := d.Load("../"); err != nil {
+		panic(err)
+	}
+	d.HideStd()
+	d.HideExternal()
+	d.SetCaption("Figure 1. Packages of the draw module")
+	d.SaveAs("img/package_diagram.svg")
+}
+
+func ExampleUseCaseDiagram() {
+	var (
+		d        = design.NewUseCaseDiagram("Web shop")
+		customer = d.Actor("Customer")
+		member   = d.Actor("Member")
+		bank     = d.SecondaryActor("Bank")
+		browse   = d.UseCase("Browse products")
+		checkout = d.UseCase("Checkout")
+		pay      = d.UseCase("Pay with card")
+		coupon   = d.UseCase("Apply coupon code")
+	)
+	d.Associate(customer, browse, checkout)
+	d.Associate(member, coupon)
+	d.Associate(bank, pay)
+	d.Include(checkout, pay)
+	d.Extend(coupon, checkout)
+	d.Generalize(member, customer)
+	d.SaveAs("img/usecase_diagram.svg")
+}
+
+func ExampleStateDiagram() {
+	var (
+		d       = design.NewStateDiagram()
+		idle    = d.State("Idle")
+		running = d.State("Running")
+		loading = running.State("Loading")
+		serving = running.State("Serving")
+	)
+	d.Initial(idle)
+	start := d.Transition(idle, running, "start")
+	start.Guard = "ready"
+	start.Action = "init()"
+	d.Initial(loading)
+	d.Transition(loading, serving, "loaded")
+	d.Transition(serving, serving, "request")
+	d.Transition(running, idle, "pause")
+	d.Final(running, "stop")
+	d.SetCaption("Figure 1. Server states")
+	d.SaveAs("img/state_diagram.svg")
+}
+
 func TestExamples(t *testing.T) {
 	ExampleClassDiagram()
 	//ExampleSequenceDiagram()
 	ExampleDiagram()
 	ExampleActivityDiagram()
+	ExampleActivityDiagram_lanes()
+	ExampleActivityDiagram_loops()
 	ExampleGanttChart()
 	ExampleGanttChart_year()
+	ExampleGanttChart_dependencies()
+	ExampleGanttChart_calendar()
+	ExampleGanttChart_months()
+	ExampleGanttChart_resources()
+	ExampleLoadGanttChart()
+	ExampleLoadERDiagram()
+	ExampleC4Diagram()
+	ExampleC4Diagram_containers()
+	ExampleDeploymentDiagram()
+	ExampleDiagram_group()
+	ExampleDiagram_anchors()
+	ExampleDiagram_linkFirst()
+	ExampleDiagram_arrowLabels()
+	ExampleDiagram_uml()
+	ExampleDiagram_decoration()
+	ExampleDiagram_fills()
+	ExampleDiagram_markers()
+	ExampleDiagram_images()
+	ExamplePackageDiagram()
+	ExampleStateDiagram()
+	ExampleUseCaseDiagram()
 }
@@ -8,12 +8,24 @@ import (
 	"github.com/gregoryv/draw"
 	"github.com/gregoryv/draw/shape"
 	"github.com/gregoryv/draw/types/date"
+	"github.com/gregoryv/draw/xy"
 )
 
 // NewGanttChart returns a GanttChart spanning days from the given
-// date. Panics if date cannot be resolved.
+// date, see date.Parse for accepted formats. Relative dates are
+// resolved from today. If the date cannot be parsed the error is
+// returned by WriteSVG.
 func NewGanttChart(from date.String, days int) *GanttChart {
-	return newGanttChart(from.Time(), days)
+	start, err := from.Parse(today())
+	d := newGanttChart(start, days)
+	d.fail(err)
+	return d
+}
+
+// today returns the current date in UTC without time of day.
+func today() time.Time {
+	y, m, d := time.Now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
 }
 
 // newGanttChart returns a chart showing days from optional
@@ -43,22 +55,53 @@ type GanttChart struct {
 	colSpace        int // between day or week
 	rowSpace        int // between tasks
 
-	// Set a marker at this date.
+	// Set a marker at this time, drawn as a vertical line.
 	Mark time.Time
 
+	// Scale sets the time span of each column, default is days.
+	Scale Scale
+
+	// Weeks is the same as setting Scale to ScaleWeeks.
 	Weeks bool
+
+	// ShowCritical highlights tasks on the critical path
+	ShowCritical bool
+
+	// Calendar, if set, makes task durations count working days
+	// only. Non-working days are shaded.
+	Calendar *Calendar
+
+	// GroupByResource shows tasks grouped in one lane per assigned
+	// resource.
+	GroupByResource bool
+
+	// ShowUtilisation adds a histogram per resource below the tasks.
+	ShowUtilisation bool
+
+	err error // first failure, returned by WriteSVG
 }
 
-func (g *GanttChart) MarkDate(yyyymmdd date.String) {
-	g.Mark = yyyymmdd.Time()
+// MarkDate sets the marker to the given date, see date.Parse for
+// accepted formats. Relative dates are resolved from the chart start.
+func (g *GanttChart) MarkDate(v date.String) {
+	t, err := v.Parse(g.start)
+	if err != nil {
+		g.fail(err)
+		return
+	}
+	g.Mark = t
 }
 
-// isToday returns true if time.Now matches start + ndays
-func (g *GanttChart) isToday(ndays int) bool {
-	t := g.start.AddDate(0, 0, ndays)
-	sameYear := t.Year() == g.Mark.Year()
-	sameDay := t.YearDay() == g.Mark.YearDay()
-	return sameYear && sameDay
+// Err returns the first error from parsing dates or scheduling
+// tasks with cyclic dependencies, if any.
+func (g *GanttChart) Err() error {
+	return g.err
+}
+
+func (g *GanttChart) fail(err error) {
+	if g.err == nil {
+		g.err = err
+	}
 }
 
 // Add new task from start spanning 3 days. Default color is green.
@@ -73,22 +116,181 @@ func (g *GanttChart) Place(task *Task) *GanttAdjuster {
 	return &GanttAdjuster{
 		start: g.start,
 		task:  task,
+		chart: g,
 	}
 }
 
+// Milestone adds a milestone, shown as a diamond. Place it with zero
+// days.
+func (g *GanttChart) Milestone(txt string) *Task {
+	task := g.Add(txt)
+	task.milestone = true
+	task.class = "milestone"
+	return task
+}
+
+// Group adds a summary task spanning the given tasks. The tasks are
+// shown indented below the summary.
+func (g *GanttChart) Group(txt string, tasks ...*Task) *Task {
+	sum := g.Add(txt)
+	sum.class = "gantt-summary"
+	for _, t := range tasks {
+		t.setParent(sum)
+	}
+	g.schedule()
+	return sum
+}
+
 type GanttAdjuster struct {
 	start time.Time
 	task  *Task
+	chart *GanttChart
+	err   error // first failure, also recorded in the chart
 }
 
+// At places the task at the given date spanning days, removing any
+// dependencies. See date.Parse for accepted formats, relative dates
+// are resolved from the chart start. Bad dates are reported by Err
+// and the charts WriteSVG.
 func (a *GanttAdjuster) At(from date.String, days int) {
-	a.task.from = from.Time()
-	a.task.to = from.Time().AddDate(0, 0, days)
+	t, err := from.Parse(a.start)
+	if err != nil {
+		a.fail(err)
+		return
+	}
+	a.at(t, days)
 }
 
+// Err returns the first error from adjusting the task, if any.
+func (a *GanttAdjuster) Err() error {
+	return a.err
+}
+
+func (a *GanttAdjuster) fail(err error) {
+	if a.err == nil {
+		a.err = err
+	}
+	if a.chart != nil {
+		a.chart.fail(err)
+	}
+}
+
+func (a *GanttAdjuster) at(from time.Time, days int) {
+	a.task.from = from
+	a.task.days = days
+	a.task.span = 0
+	a.task.deps = nil
+	a.reschedule()
+}
+
+// AtTime places the task at the given time spanning d, removing any
+// dependencies. Use it with ScaleHours for tasks shorter than a day,
+// e.g. in incident timelines.
+func (a *GanttAdjuster) AtTime(from time.Time, d time.Duration) {
+	a.task.from = from
+	a.task.days = 0
+	a.task.span = d
+	a.task.deps = nil
+	a.reschedule()
+}
+
+// After places the task spanning days when the parent finishes.
 func (a *GanttAdjuster) After(parent *Task, days int) {
-	a.task.from = parent.to
-	a.task.to = parent.to.AddDate(0, 0, days)
+	a.Depend(parent, FinishToStart, days)
+}
+
+// Depend adds a dependency of the given kind on parent. The task
+// spans days and is moved whenever the parent moves.
+func (a *GanttAdjuster) Depend(parent *Task, kind Dependency, days int) {
+	a.task.days = days
+	a.task.span = 0
+	a.task.deps = append(a.task.deps, &dependency{
+		parent: parent,
+		kind:   kind,
+	})
+	a.reschedule()
+}
+
+// reschedule updates dates of all tasks in the chart or only the
+// adjusted task if it's not in a chart.
+func (a *GanttAdjuster) reschedule() {
+	if a.chart != nil {
+		a.chart.schedule()
+		return
+	}
+	a.fail(a.task.schedule(make(map[*Task]bool), nil))
+}
+
+// Dependency defines how a task is scheduled relative to the task it
+// depends on.
+type Dependency int
+
+const (
+	// FinishToStart starts the task when the parent finishes
+	FinishToStart Dependency = iota
+	// StartToStart starts the task when the parent starts
+	StartToStart
+	// FinishToFinish finishes the task when the parent finishes
+	FinishToFinish
+	// StartToFinish finishes the task when the parent starts
+	StartToFinish
+)
+
+type dependency struct {
+	parent *Task
+	kind   Dependency
+}
+
+// start returns the earliest start of a task spanning days.
+func (d *dependency) start(days int, c *Calendar) time.Time {
+	switch d.kind {
+	case StartToStart:
+		return d.parent.from
+	case FinishToFinish:
+		return c.AddWorkdays(d.parent.to, -days)
+	case StartToFinish:
+		return c.AddWorkdays(d.parent.from, -days)
+	default:
+		return d.parent.to
+	}
+}
+
+// schedule updates the dates of dependent and summary tasks.
+// Cyclic dependencies are reported by Err.
+func (g *GanttChart) schedule() {
+	done := make(map[*Task]bool)
+	for _, t := range g.tasks {
+		g.fail(t.schedule(done, g.Calendar))
+	}
+}
+
+// rows returns the tasks in the order they are shown, ie. grouped
+// tasks directly below their summary.
+func (g *GanttChart) rows() []*Task {
+	if g.GroupByResource {
+		return g.laneRows()
+	}
+	rows := make([]*Task, 0, len(g.tasks))
+	seen := make(map[*Task]bool)
+	var add func(t *Task)
+	add = func(t *Task) {
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+		rows = append(rows, t)
+		for _, c := range t.children {
+			add(c)
+		}
+	}
+	for _, t := range g.tasks {
+		top := t
+		for top.parent != nil {
+			top = top.parent
+		}
+		add(top)
+	}
+	return rows
 }
 
 func (d *GanttChart) SetRowSpace(rowSpace int) {
@@ -96,123 +298,301 @@ func (d *GanttChart) SetRowSpace(rowSpace int) {
 }
 
 func (d *GanttChart) WriteSVG(w io.Writer) error {
-	columns := d.addHeader()
-	bars := make([]*shape.Rect, len(d.tasks))
-	start := d.padLeft + d.taskWidth()
+	if d.err != nil {
+		return d.err
+	}
+	d.schedule()
+	rows := d.rows()
+	line := d.addHeader()
+	bars := make([]*shape.Rect, len(rows))
 	lineHeight := d.Diagram.Font.LineHeight
-	headerHeight := d.padTop + lineHeight*3
-	for i, t := range d.tasks {
+	headerHeight := d.headerHeight()
+	critical := make(map[*Task]bool)
+	if d.ShowCritical {
+		for _, t := range d.CriticalPath() {
+			critical[t] = true
+		}
+	}
+	for i, t := range rows {
 		rect := shape.NewRect("")
 		rect.SetHeight(d.Diagram.Font.Height)
 		rect.SetClass(t.class)
+		if critical[t] && !t.milestone {
+			rect.SetClass("span-critical")
+		}
 		bars[i] = rect
 		d.drawTask(i, t)
 		y := i*lineHeight + headerHeight + i*d.rowSpace
-		d.Diagram.Place(rect).At(start, y)
-	}
-	// adjust the bars
-
-	for j, t := range d.tasks {
-		var width int
-		for i := 0; i < d.days; i++ {
-			now := d.start.AddDate(0, 0, i)
-			var col *shape.Label
-			if d.Weeks {
-				col = columns[i/7]
-			} else {
-				col = columns[i]
+		x1, x2 := line.span(t.from, t.to)
+		width := x2 - x1
+		if width > 2*d.colSpace {
+			width -= d.colSpace // gap between following tasks
+		}
+		rect.SetX(x1)
+		rect.SetY(y)
+		rect.SetWidth(width)
+		if t.milestone || t.lane || width <= 0 {
+			continue
+		}
+		d.Diagram.Place(rect)
+	}
+	for i, t := range rows {
+		d.decorate(t, bars[i])
+	}
+	d.drawDependencies(rows, bars, critical)
+	d.drawOverallocations(line, rows, bars)
+	if d.ShowUtilisation {
+		d.drawUtilisation(line)
+	}
+	if line.contains(d.Mark) {
+		x := line.x(d.Mark)
+		mark := shape.NewLine(x, d.columnsY(), x, d.bottom())
+		mark.SetClass("mark")
+		d.Diagram.Place(mark)
+	}
+	return d.Diagram.WriteSVG(w)
+}
+
+// scale returns the scale to use, considering the Weeks setting.
+func (d *GanttChart) scale() Scale {
+	if d.Weeks && d.Scale == ScaleDays {
+		return ScaleWeeks
+	}
+	return d.Scale
+}
+
+// rowHeight returns the height of each header row.
+func (d *GanttChart) rowHeight() int {
+	return d.Diagram.Font.LineHeight + d.colSpace
+}
+
+// columnsY returns the y position of the column labels.
+func (d *GanttChart) columnsY() int {
+	return d.padTop + len(d.scale().headers())*d.rowHeight()
+}
+
+// headerHeight returns the y position of the first task.
+func (d *GanttChart) headerHeight() int {
+	return d.columnsY() + d.rowHeight() + 2*d.colSpace
+}
+
+// bottom returns the y position below the last task.
+func (d *GanttChart) bottom() int {
+	n := len(d.rows())
+	return d.headerHeight() + n*(d.Diagram.Font.LineHeight+d.rowSpace)
+}
+
+// decorate draws milestones, summaries and progress of the task
+// bar.
+func (d *GanttChart) decorate(t *Task, bar *shape.Rect) {
+	h := bar.Height()
+	switch {
+	case t.milestone:
+		m := shape.NewDiamond()
+		m.SetClass(t.class)
+		m.SetWidth(h)
+		m.SetHeight(h)
+		m.SetX(bar.X - h/2)
+		m.SetY(bar.Y)
+		d.Diagram.Place(m)
+		// let dependencies point at the diamond
+		bar.SetX(bar.X - h/2)
+		bar.SetWidth(h)
+	case len(t.children) > 0:
+		// summaries are thinner than tasks
+		bar.SetHeight(h / 2)
+		bar.SetY(bar.Y + h/4)
+	case t.progress > 0:
+		done := shape.NewRect("")
+		done.SetClass("gantt-progress")
+		done.SetHeight(h)
+		done.SetWidth(bar.Width() * t.progress / 100)
+		d.Diagram.Place(done).At(bar.X, bar.Y)
+	}
+}
+
+// drawDependencies draws an arrow for each dependency between shown
+// tasks, from the start or finish of the parent to the start or
+// finish of the dependent task. Arrows between critical tasks are
+// highlighted.
+func (d *GanttChart) drawDependencies(rows []*Task, bars []*shape.Rect, critical map[*Task]bool) {
+	index := make(map[*Task]int)
+	for i, t := range rows {
+		index[t] = i
+	}
+	for i, t := range rows {
+		for _, dep := range t.deps {
+			j, found := index[dep.parent]
+			if !found {
+				continue
 			}
-			switch {
-			case now.Equal(t.from):
-				d.VAlignLeft(col, bars[j])
-			case now.After(t.from) && now.Before(t.to) || now.Equal(t.to):
-				if !d.Weeks || (d.Weeks && now.Weekday() == time.Sunday) {
-					width += col.Width()
-					width += d.colSpace
-				}
+			fromFinish := dep.kind == FinishToStart || dep.kind == FinishToFinish
+			toFinish := dep.kind == FinishToFinish || dep.kind == StartToFinish
+			a := ganttArrow(bars[j], bars[i], fromFinish, toFinish)
+			a.SetClass("gantt-arrow")
+			if critical[t] && critical[dep.parent] {
+				a.SetClass("critical-arrow")
 			}
+			d.Diagram.Place(a)
 		}
-		if !d.Weeks {
-			width -= d.colSpace
-		}
-		bars[j].SetWidth(width)
 	}
+}
 
-	return d.Diagram.WriteSVG(w)
+// ganttArrow returns an arrow with orthogonal segments from the
+// start, or finish, of one bar to the start, or finish, of another.
+func ganttArrow(from, to *shape.Rect, fromFinish, toFinish bool) *shape.Arrow {
+	const gap = 6
+	start := xy.Point{X: from.X, Y: from.Y + from.Height()/2}
+	out := -1 // leave to the left
+	if fromFinish {
+		start.X += from.Width()
+		out = 1
+	}
+	end := xy.Point{X: to.X, Y: to.Y + to.Height()/2}
+	in := 1 // enter from the left
+	if toFinish {
+		end.X += to.Width()
+		in = -1
+	}
+	a := shape.NewArrow(start.X, start.Y, end.X, end.Y)
+	x1 := start.X + out*gap
+	x2 := end.X - in*gap
+	switch {
+	case out != in && out > 0:
+		x := x1
+		if x2 > x {
+			x = x2
+		}
+		a.Via = []xy.Point{{X: x, Y: start.Y}, {X: x, Y: end.Y}}
+	case out != in:
+		x := x1
+		if x2 < x {
+			x = x2
+		}
+		a.Via = []xy.Point{{X: x, Y: start.Y}, {X: x, Y: end.Y}}
+	case out*(x2-x1) >= 0:
+		a.Via = []xy.Point{{X: x1, Y: start.Y}, {X: x1, Y: end.Y}}
+	case out*(end.X-start.X) >= 0:
+		// too close for a gap on both sides
+		x := (start.X + end.X) / 2
+		a.Via = []xy.Point{{X: x, Y: start.Y}, {X: x, Y: end.Y}}
+	default:
+		// turn back between the rows
+		y := (start.Y + end.Y) / 2
+		a.Via = []xy.Point{
+			{X: x1, Y: start.Y}, {X: x1, Y: y},
+			{X: x2, Y: y}, {X: x2, Y: end.Y},
+		}
+	}
+	return a
 }
 
-func (d *GanttChart) addHeader() []*shape.Label {
-	now := d.start
-	year := shape.NewLabel(fmt.Sprintf("%v", now.Year()))
-	d.Diagram.Place(year).At(d.padLeft, d.padTop)
-	offset := d.padLeft + d.taskWidth()
-
-	var lastDay *shape.Label
-	columns := make([]*shape.Label, 0)
-	var col *shape.Label
-	for i := 0; i < d.days; i++ {
-		day := now.Day()
-		colName := day
-		if d.Weeks {
-			_, colName = now.ISOWeek()
+// addHeader adds header rows and column labels, returning the
+// timeline used to place tasks.
+func (d *GanttChart) addHeader() *timeline {
+	scale := d.scale()
+	end := d.start.AddDate(0, 0, d.days)
+	var (
+		columns = make([]*shape.Label, 0)
+		times   = make([]time.Time, 0)
+		widest  int
+	)
+	for t := scale.truncate(d.start); t.Before(end); t = scale.next(t) {
+		col := newCol(scale.label(t))
+		if w := col.Width(); w > widest {
+			widest = w
 		}
-		wday := now.Weekday()
-		if day == 1 || d.Weeks && wday == 1 || !d.Weeks {
-			col = newCol(colName)
-			columns = append(columns, col)
-
-			if !d.Weeks && now.Weekday() == time.Saturday {
-				bg := shape.NewRect("")
-				bg.SetClass("weekend")
-				bg.SetWidth((col.Width() + d.colSpace) * 2)
-				bg.SetHeight(len(d.tasks)*col.Font.LineHeight +
-					d.padTop +
-					d.Diagram.Font.LineHeight +
-					d.colSpace +
-					len(d.tasks)*d.rowSpace)
-				d.Diagram.Place(bg).RightOf(lastDay, d.colSpace)
-				shape.Move(bg, -d.colSpace/2, d.colSpace)
-			}
-			if i == 0 {
-				d.Diagram.Place(col).Below(year, d.colSpace)
-				col.SetX(offset)
-			} else {
-				d.Diagram.Place(col).RightOf(lastDay, d.colSpace)
+		columns = append(columns, col)
+		times = append(times, t)
+	}
+	line := &timeline{
+		start: d.start,
+		end:   end,
+		left:  d.padLeft + d.taskWidth(),
+		pitch: float64(widest + d.colSpace),
+		scale: scale,
+	}
+	for i, h := range scale.headers() {
+		y := d.padTop + i*d.rowHeight()
+		for t := h.truncate(d.start); t.Before(end); t = h.next(t) {
+			x1, x2 := line.span(t, h.next(t))
+			label := shape.NewLabel(h.title(t, false))
+			if label.Width() > x2-x1 {
+				label = shape.NewLabel(h.title(t, true))
 			}
-			if day == 1 {
-				monthName := now.Month().String()
-				if d.Weeks {
-					monthName = monthName[:3]
-				}
-				label := shape.NewLabel(monthName)
-				d.Diagram.Place(label).Above(col, d.colSpace)
+			if label.Width() > x2-x1 && len(columns) > 1 {
+				continue // doesn't fit
 			}
-			lastDay = col
+			d.Diagram.Place(label).At(x1, y)
 		}
-		if d.isToday(i) {
-			x, y := col.Position()
-			mark := shape.NewCircle(4)
-			mark.SetX(x + 1)
-			mark.SetY(y - 1)
-			mark.SetClass("fill-red")
-			d.Diagram.Place(mark)
+	}
+	y := d.columnsY()
+	for i, col := range columns {
+		x, _ := line.span(times[i], end)
+		d.Diagram.Place(col).At(x, y)
+	}
+	d.shadeDaysOff(line)
+	return line
+}
+
+// shadeDaysOff adds a background to each sequence of non-working
+// days. Without a calendar only weekends are shaded and only if each
+// day has a column.
+func (d *GanttChart) shadeDaysOff(line *timeline) {
+	width := line.x(d.start.AddDate(0, 0, 1)) - line.x(d.start)
+	switch {
+	case width < 2:
+		return // too narrow to see
+	case d.Calendar == nil && float64(width) < line.pitch:
+		return
+	}
+	var (
+		bg      *shape.Rect
+		bgClass string
+		y       = d.columnsY() + d.colSpace
+	)
+	for t := d.start; t.Before(line.end); t = t.AddDate(0, 0, 1) {
+		class := "weekend"
+		switch {
+		case d.Calendar.IsHoliday(t):
+			class = "holiday"
+		case d.Calendar != nil && d.Calendar.IsWorkday(t):
+			bg = nil
+			continue
+		case d.Calendar == nil && t.Weekday() != time.Saturday &&
+			t.Weekday() != time.Sunday:
+			bg = nil
+			continue
+		}
+		x1, x2 := line.span(t, t.AddDate(0, 0, 1))
+		if bg != nil && bgClass == class {
+			bg.SetWidth(x2 - x1 + bg.Width())
+			continue
 		}
-		now = now.AddDate(0, 0, 1)
+		bg, bgClass = shape.NewRect(""), class
+		bg.SetClass(class)
+		bg.SetWidth(x2 - x1)
+		bg.SetHeight(d.bottom() - y)
+		bg.SetX(x1 - d.colSpace/2)
+		bg.SetY(y)
+		d.Diagram.Prepend(bg)
 	}
-	return columns
 }
 
 func (d *GanttChart) drawTask(i int, t *Task) {
 	label := shape.NewLabel(t.txt)
+	if t.lane {
+		label.SetClass("gantt-lane")
+	}
 	lineHeight := d.Diagram.Font.LineHeight
-	headerHeight := d.padTop + lineHeight*3
-	x := d.padLeft
+	headerHeight := d.headerHeight()
+	x := d.padLeft + d.indent(t)*d.padLeft
 	y := i*lineHeight + headerHeight - lineHeight/3 + i*d.rowSpace
 	d.Diagram.Place(label).At(x, y)
 }
 
-func newCol(day int) *shape.Label {
-	col := shape.NewLabel(fmt.Sprintf("%02v", day))
+func newCol(txt string) *shape.Label {
+	col := shape.NewLabel(txt)
 	col.Font.Height = 10
 	return col
 }
@@ -231,15 +611,34 @@ func (d *GanttChart) String() string { return toString(d) }
 
 func (d *GanttChart) taskWidth() int {
 	x := 0
-	for _, t := range d.tasks {
-		w := d.Diagram.Font.TextWidth(t.txt)
+	for _, t := range d.rows() {
+		w := d.Diagram.Font.TextWidth(t.txt) + d.indent(t)*d.padLeft
 		if w > x {
 			x = w
 		}
 	}
+	if d.ShowUtilisation {
+		for _, r := range d.Resources() {
+			if w := d.Diagram.Font.TextWidth(r); w > x {
+				x = w
+			}
+		}
+	}
 	return x + d.padLeft
 }
 
+// indent returns the number of indentations of the task name.
+func (d *GanttChart) indent(t *Task) int {
+	switch {
+	case !d.GroupByResource:
+		return t.depth()
+	case t.lane:
+		return 0
+	default:
+		return 1
+	}
+}
+
 // NewTask returns a green task.
 func NewTask(txt string) *Task {
 	return &Task{
@@ -252,7 +651,114 @@ func NewTask(txt string) *Task {
 type Task struct {
 	txt      string
 	from, to time.Time
+	days     int
+	span     time.Duration // of tasks placed with AtTime
 	class    string
+
+	milestone bool
+	progress  int // percent complete
+	deps      []*dependency
+	parent    *Task // summary task
+	children  []*Task
+
+	resources []string
+	lane      bool // resource name row when grouped by resource
+}
+
+// From returns the date the task starts.
+func (t *Task) From() time.Time { return t.from }
+
+// To returns the date the task ends.
+func (t *Task) To() time.Time { return t.to }
+
+// Progress sets percent complete of the task, shown as a darker
+// part of the bar.
+func (t *Task) Progress(percent int) *Task {
+	switch {
+	case percent < 0:
+		percent = 0
+	case percent > 100:
+		percent = 100
+	}
+	t.progress = percent
+	return t
+}
+
+func (t *Task) setParent(p *Task) {
+	if t.parent != nil {
+		children := t.parent.children[:0]
+		for _, c := range t.parent.children {
+			if c != t {
+				children = append(children, c)
+			}
+		}
+		t.parent.children = children
+	}
+	t.parent = p
+	p.children = append(p.children, t)
+}
+
+// depth returns the number of summary tasks above t.
+func (t *Task) depth() int {
+	var n int
+	for p := t.parent; p != nil; p = p.parent {
+		n++
+	}
+	return n
+}
+
+// schedule updates the dates of t from its dependencies or children,
+// scheduling them first. Tasks in done are skipped, the value is
+// false while a task is being scheduled which breaks cyclic
+// dependencies. The first cycle found is returned.
+func (t *Task) schedule(done map[*Task]bool, c *Calendar) error {
+	if finished, found := done[t]; found {
+		if !finished {
+			return fmt.Errorf("cyclic dependency on task %q", t.txt)
+		}
+		return nil
+	}
+	done[t] = false
+	defer func() { done[t] = true }()
+	var err error
+	for _, child := range t.children {
+		if e := child.schedule(done, c); err == nil {
+			err = e
+		}
+	}
+	for _, dep := range t.deps {
+		if e := dep.parent.schedule(done, c); err == nil {
+			err = e
+		}
+	}
+	if len(t.children) > 0 {
+		t.from, t.to = t.children[0].from, t.children[0].to
+		for _, c := range t.children[1:] {
+			if c.from.Before(t.from) {
+				t.from = c.from
+			}
+			if c.to.After(t.to) {
+				t.to = c.to
+			}
+		}
+		return err
+	}
+	for i, dep := range t.deps {
+		if s := dep.start(t.days, c); i == 0 || s.After(t.from) {
+			t.from = s
+		}
+	}
+	if len(t.deps) > 0 {
+		t.from = c.nextWorkday(t.from)
+	}
+	if t.span > 0 {
+		t.to = t.from.Add(t.span)
+		return err
+	}
+	// the end is resolved here so the calendar of the chart applies
+	// to all tasks, whenever it's set
+	t.to = c.AddWorkdays(t.from, t.days)
+	return err
 }
 
 // Red sets class of task to span-red
@@ -2,9 +2,11 @@ package design
 
 import (
 	"bytes"
+	"io/ioutil"
 	"strings"
 	"testing"
 
+	"github.com/gregoryv/asserter"
 	"github.com/gregoryv/draw/types/date"
 	"github.com/gregoryv/golden"
 )
@@ -37,12 +39,90 @@ func TestGanttChart_WriteSvg(t *testing.T) {
 }
 
 func TestGanttChart_Inline(t *testing.T) {
-	got := newTestChart().Inline()
-	if strings.Contains(got, "class") {
-		t.Error("found class attributes\n", got)
+	for _, d := range []*GanttChart{newTestChart(), newDependencyChart()} {
+		got := d.Inline()
+		if strings.Contains(got, "class") {
+			t.Error("found class attributes\n", got)
+		}
 	}
 }
 
+func TestGanttAdjuster_After_moves(t *testing.T) {
+	var (
+		d   = NewGanttChart("20191111", 30)
+		dev = d.Add("Develop")
+		rel = d.Add("Release")
+	)
+	d.Place(dev).At("20191111", 10)
+	d.Place(rel).After(dev, 2)
+	assert := asserter.New(t)
+	assert().Equals(rel.From(), date.String("20191121").Time())
+	// moving upstream task updates dependent tasks
+	d.Place(dev).At("20191113", 10)
+	assert().Equals(rel.From(), date.String("20191123").Time())
+	assert().Equals(rel.To(), date.String("20191125").Time())
+}
+
+func TestGanttAdjuster_Depend(t *testing.T) {
+	d := NewGanttChart("20191111", 30)
+	a := d.Add("a")
+	d.Place(a).At("20191111", 10)
+	ok := func(kind Dependency, from, to date.String) {
+		t.Helper()
+		b := d.Add("b")
+		d.Place(b).Depend(a, kind, 4)
+		if !b.From().Equal(from.Time()) || !b.To().Equal(to.Time()) {
+			t.Errorf("%v: got %v - %v", kind, b.From(), b.To())
+		}
+	}
+	ok(FinishToStart, "20191121", "20191125")
+	ok(StartToStart, "20191111", "20191115")
+	ok(FinishToFinish, "20191117", "20191121")
+	ok(StartToFinish, "20191107", "20191111")
+}
+
+func TestGanttChart_Group(t *testing.T) {
+	d := newDependencyChart()
+	rows := d.rows()
+	assert := asserter.New(t)
+	assert().Equals(rows[0].txt, "Develop")
+	assert().Equals(rows[1].txt, "Design")
+	assert().Equals(rows[1].depth(), 1)
+	sum := rows[0]
+	assert().Equals(sum.From(), date.String("20191111").Time())
+	assert().Equals(sum.To(), date.String("20191121").Time())
+	got := d.String()
+	assert().Contains(got, `class="gantt-summary"`)
+	assert().Contains(got, `class="gantt-progress"`)
+	assert().Contains(got, `class="gantt-arrow"`)
+	assert().Contains(got, `class="milestone"`)
+}
+
+func TestTask_Progress(t *testing.T) {
+	task := NewTask("x")
+	if task.Progress(150).progress != 100 {
+		t.Error("progress above 100")
+	}
+	if task.Progress(-1).progress != 0 {
+		t.Error("progress below 0")
+	}
+}
+
+func TestGanttChart_cyclicDependency(t *testing.T) {
+	var (
+		d = NewGanttChart("20191111", 30)
+		a = d.Add("a")
+		b = d.Add("b")
+	)
+	d.Place(a).At("20191111", 2)
+	d.Place(b).After(a, 2)
+	d.Place(a).After(b, 2)
+	assert := asserter.New(t)
+	assert(d.Err() != nil).Fatal("expected cycle error")
+	assert().Contains(d.Err().Error(), "cyclic dependency")
+	assert().Equals(d.WriteSVG(ioutil.Discard), d.Err())
+}
+
 func TestGanttChart_String(t *testing.T) {
 	got := newTestChart().String()
 	if !strings.Contains(got, "class") {
@@ -56,9 +136,22 @@ func TestNewGanttChart(t *testing.T) {
 	NewGanttChart("20190228", 20)
 }
 
-func TestNewGanttChartFrom_panics(t *testing.T) {
-	defer expectPanic(t)
-	NewGanttChart("201910-2", 20)
+func TestNewGanttChart_bad_date(t *testing.T) {
+	d := NewGanttChart("201910-2", 20)
+	if err := d.WriteSVG(ioutil.Discard); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestNewGanttChart_formats(t *testing.T) {
+	for _, v := range []date.String{
+		"20191111", "2019-11-11", "2019-W46-1", "+0d", "-1w",
+	} {
+		d := NewGanttChart(v, 20)
+		if err := d.Err(); err != nil {
+			t.Error(v, err)
+		}
+	}
 }
 
 func TestGanttChart_MarkDate(t *testing.T) {
@@ -67,10 +160,35 @@ func TestGanttChart_MarkDate(t *testing.T) {
 	d.MarkDate("20191204") // Ok even if it's outside the visible span
 }
 
-func TestGanttChart_MarkDate_panics(t *testing.T) {
-	defer expectPanic(t)
+func TestGanttChart_MarkDate_relative(t *testing.T) {
+	d := NewGanttChart("20191002", 20)
+	d.MarkDate("+1w")
+	if exp := date.String("20191009").Time(); !d.Mark.Equal(exp) {
+		t.Errorf("got %v, expected %v", d.Mark, exp)
+	}
+}
+
+func TestGanttChart_MarkDate_bad_date(t *testing.T) {
 	d := NewGanttChart("20191002", 20)
 	d.MarkDate("")
+	if err := d.WriteSVG(ioutil.Discard); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestGanttAdjuster_At_bad_date(t *testing.T) {
+	d := NewGanttChart("20191002", 20)
+	a := d.Place(d.Add("x"))
+	a.At("2019-W60", 2)
+	if d.Err() == nil || a.Err() != d.Err() {
+		t.Error("expected error in both chart and adjuster")
+	}
+	// tasks not in a chart
+	a = &GanttAdjuster{task: NewTask("y")}
+	a.At("hello", 2)
+	if a.Err() == nil {
+		t.Error("expected error")
+	}
 }
 
 func newTestChart() *GanttChart {
@@ -88,6 +206,20 @@ func newTestChart() *GanttChart {
 	return d
 }
 
+func newDependencyChart() *GanttChart {
+	var (
+		d      = NewGanttChart("20191111", 30)
+		design = d.Add("Design").Progress(100)
+		build  = d.Add("Build").Progress(40)
+		dev    = d.Group("Develop", design, build)
+		done   = d.Milestone("Release")
+	)
+	d.Place(design).At("20191111", 4)
+	d.Place(build).After(design, 6)
+	d.Place(done).After(dev, 0)
+	return d
+}
+
 func expectPanic(t *testing.T) {
 	t.Helper()
 	e := recover()
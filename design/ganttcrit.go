@@ -0,0 +1,91 @@
+package design
+
+import "time"
+
+// Slack returns the number of days task t can be delayed without
+// delaying the end of the chart, ie. the latest end of all tasks.
+// Tasks on the critical path have zero slack. Only working days are
+// counted if the chart has a calendar.
+func (g *GanttChart) Slack(t *Task) int {
+	g.schedule()
+	return g.Calendar.Workdays(t.to, g.latestFinish()[t])
+}
+
+// CriticalPath returns the tasks without slack in the order they are
+// shown. Summary tasks are not included.
+func (g *GanttChart) CriticalPath() []*Task {
+	g.schedule()
+	latest := g.latestFinish()
+	path := make([]*Task, 0)
+	for _, t := range g.rows() {
+		if len(t.children) == 0 && g.Calendar.Workdays(t.to, latest[t]) <= 0 {
+			path = append(path, t)
+		}
+	}
+	return path
+}
+
+// latestFinish returns the latest date each task can finish without
+// delaying the end of the chart.
+func (g *GanttChart) latestFinish() map[*Task]time.Time {
+	var end time.Time
+	for _, t := range g.tasks {
+		if t.to.After(end) {
+			end = t.to
+		}
+	}
+	type successor struct {
+		task *Task
+		kind Dependency
+	}
+	next := make(map[*Task][]successor)
+	for _, t := range g.tasks {
+		for _, dep := range t.deps {
+			next[dep.parent] = append(next[dep.parent], successor{t, dep.kind})
+		}
+	}
+	c := g.Calendar
+	latest := make(map[*Task]time.Time)
+	var finish func(t *Task) time.Time
+	finish = func(t *Task) time.Time {
+		if v, found := latest[t]; found {
+			return v
+		}
+		lf := end
+		latest[t] = lf // breaks cyclic dependencies
+		for _, s := range next[t] {
+			sf := finish(s.task)
+			var v time.Time
+			switch s.kind {
+			case FinishToStart:
+				v = c.AddWorkdays(sf, -s.task.duration(c))
+			case StartToStart:
+				ls := c.AddWorkdays(sf, -s.task.duration(c))
+				v = c.AddWorkdays(ls, t.duration(c))
+			case FinishToFinish:
+				v = sf
+			case StartToFinish:
+				v = c.AddWorkdays(sf, t.duration(c))
+			}
+			if v.Before(lf) {
+				lf = v
+			}
+		}
+		if t.parent != nil {
+			if v := finish(t.parent); v.Before(lf) {
+				lf = v
+			}
+		}
+		latest[t] = lf
+		return lf
+	}
+	for _, t := range g.tasks {
+		finish(t)
+	}
+	return latest
+}
+
+// duration returns the number of working days the task spans.
+func (t *Task) duration(c *Calendar) int {
+	return c.Workdays(t.from, t.to)
+}
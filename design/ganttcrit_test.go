@@ -0,0 +1,52 @@
+package design
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+)
+
+func TestGanttChart_CriticalPath(t *testing.T) {
+	var (
+		d    = NewGanttChart("20191111", 30)
+		spec = d.Add("Design")
+		code = d.Add("Build")
+		docs = d.Add("Document")
+		test = d.Add("Test")
+		dev  = d.Group("Develop", spec, code)
+		rel  = d.Milestone("Release")
+	)
+	d.Place(spec).At("20191111", 4)
+	d.Place(code).After(spec, 6)
+	d.Place(docs).Depend(spec, StartToStart, 2)
+	d.Place(test).Depend(code, FinishToFinish, 3)
+	d.Place(rel).After(dev, 0)
+
+	assert := asserter.New(t)
+	assert().Equals(d.CriticalPath(), []*Task{spec, code, test, rel})
+	assert().Equals(d.Slack(spec), 0)
+	assert().Equals(d.Slack(docs), 8)
+	assert().Equals(d.Slack(dev), 0)
+
+	// moving the end extends slack of all other tasks
+	d.Place(docs).At("20191115", 10)
+	assert().Equals(d.CriticalPath(), []*Task{docs})
+	assert().Equals(d.Slack(code), 4)
+	assert().Equals(d.Slack(test), 4)
+}
+
+func TestGanttChart_ShowCritical(t *testing.T) {
+	d := newDependencyChart()
+	d.ShowCritical = true
+	got := d.String()
+	if !strings.Contains(got, `class="span-critical"`) {
+		t.Error("missing critical bars\n", got)
+	}
+	if !strings.Contains(got, `class="critical-arrow"`) {
+		t.Error("missing critical arrows\n", got)
+	}
+	if got := d.Inline(); strings.Contains(got, "class") {
+		t.Error("found class attributes\n", got)
+	}
+}
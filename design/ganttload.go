@@ -0,0 +1,390 @@
+package design
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadGanttChart returns a chart with the tasks found in the given
+// iCalendar file, if it ends with .ics, or CSV file. See
+// ReadGanttCSV and ReadGanttICS.
+func LoadGanttChart(filename string) (*GanttChart, error) {
+	fh, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	if strings.EqualFold(filepath.Ext(filename), ".ics") {
+		return ReadGanttICS(fh)
+	}
+	return ReadGanttCSV(fh)
+}
+
+// ReadGanttCSV returns a chart with one task per row. Columns are
+//
+//	name, start, end or duration, color, dependencies...
+//
+// Dates are given as yyyymmdd or yyyy-mm-dd, the end date is
+// included in the task. Durations are given in days, e.g. 5 or 5d,
+// or weeks, e.g. 2w. A zero duration makes the task a milestone.
+// The color is one of green, red, blue, yellow, orange or a class
+// name. Each dependency names another task which must finish before
+// the task starts, other dependencies are given with a suffix, e.g.
+// Design:SS for start-to-start. The start may be empty for tasks with
+// dependencies. A first row starting with name is ignored.
+//
+// Rows with errors, or repeating the name of an earlier row, are
+// skipped and returned as ImportErrors together with the chart of all
+// other rows.
+func ReadGanttCSV(r io.Reader) (*GanttChart, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	rows := make([]*importRow, 0)
+	var errs ImportErrors
+	for n := 1; ; n++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n == 1 && strings.EqualFold(rec[0], "name") {
+			continue // header
+		}
+		row, err := parseCSVRow(rec)
+		if err != nil {
+			errs = append(errs, &RowError{Row: n, Err: err})
+			continue
+		}
+		row.n = n
+		rows = append(rows, row)
+	}
+	return newImportedChart(rows, errs)
+}
+
+// parseCSVRow parses one task of the form described in ReadGanttCSV.
+func parseCSVRow(rec []string) (*importRow, error) {
+	for len(rec) < 3 {
+		rec = append(rec, "")
+	}
+	row := &importRow{name: strings.TrimSpace(rec[0])}
+	if row.name == "" {
+		return nil, fmt.Errorf("missing name")
+	}
+	var err error
+	if v := strings.TrimSpace(rec[1]); v != "" {
+		if row.start, err = parseDay(v); err != nil {
+			return nil, fmt.Errorf("start: %w", err)
+		}
+	}
+	v := strings.TrimSpace(rec[2])
+	if v == "" {
+		return nil, fmt.Errorf("missing end or duration")
+	}
+	if end, err := parseDay(v); err == nil {
+		if row.start.IsZero() {
+			return nil, fmt.Errorf("end date without start")
+		}
+		row.days = daysIn(end.AddDate(0, 0, 1).Sub(row.start))
+		if row.days < 1 {
+			return nil, fmt.Errorf("end %s before start", v)
+		}
+	} else if row.days, err = parseDuration(v); err != nil {
+		return nil, fmt.Errorf("bad end or duration %q", v)
+	}
+	if len(rec) > 3 {
+		row.class = colorClass(strings.TrimSpace(rec[3]))
+	}
+	for _, dep := range rec[min(len(rec), 4):] {
+		if dep = strings.TrimSpace(dep); dep != "" {
+			row.deps = append(row.deps, dep)
+		}
+	}
+	if row.start.IsZero() && len(row.deps) == 0 {
+		return nil, fmt.Errorf("missing start")
+	}
+	return row, nil
+}
+
+// parseDuration parses days, e.g. 5 or 5d, or weeks, e.g. 2w.
+func parseDuration(v string) (int, error) {
+	factor := 1
+	switch {
+	case strings.HasSuffix(v, "d"):
+		v = v[:len(v)-1]
+	case strings.HasSuffix(v, "w"):
+		v, factor = v[:len(v)-1], 7
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("bad duration %q", v)
+	}
+	return n * factor, nil
+}
+
+// colorClass returns the class of the named color, other names are
+// used as is.
+func colorClass(v string) string {
+	switch strings.ToLower(v) {
+	case "", "green":
+		return "span-green"
+	case "red", "blue", "yellow", "orange":
+		return "span-" + strings.ToLower(v)
+	}
+	return v
+}
+
+// ReadGanttICS returns a chart with one task per event or todo.
+// Events span DTSTART to DTEND or DURATION, todos span DTSTART to
+// DUE. Ends with a time of day include that day. Todos without start
+// are milestones at the due date. PERCENT-COMPLETE is shown as
+// progress.
+//
+// Components with errors, or repeating the summary of an earlier
+// component, are skipped and returned as ImportErrors together with
+// the chart of all other components. The row of each error is the
+// line where the component begins.
+func ReadGanttICS(r io.Reader) (*GanttChart, error) {
+	components, err := readICS(r)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]*importRow, 0)
+	var errs ImportErrors
+	for _, c := range components {
+		if c.kind != "VEVENT" && c.kind != "VTODO" {
+			continue
+		}
+		row, err := parseICSRow(c)
+		if err != nil {
+			errs = append(errs, &RowError{Row: c.line, Err: err})
+			continue
+		}
+		row.n = c.line
+		rows = append(rows, row)
+	}
+	return newImportedChart(rows, errs)
+}
+
+func parseICSRow(c *icsComponent) (*importRow, error) {
+	row := &importRow{
+		name:  c.props["SUMMARY"],
+		class: "span-green",
+	}
+	if row.name == "" {
+		row.name = c.props["UID"]
+	}
+	end := "DTEND"
+	if c.kind == "VTODO" {
+		end = "DUE"
+	}
+	_, hasStart := c.props["DTSTART"]
+	_, hasEnd := c.props[end]
+	var err error
+	switch {
+	case hasStart:
+		if row.start, err = c.date("DTSTART"); err != nil {
+			return nil, err
+		}
+	case hasEnd:
+		// milestone at the end date
+	default:
+		return nil, fmt.Errorf("%s: missing DTSTART", c.kind)
+	}
+	switch {
+	case hasEnd && !hasStart:
+		if row.start, err = c.date(end); err != nil {
+			return nil, err
+		}
+	case hasEnd:
+		to, err := c.endDay(end)
+		if err != nil {
+			return nil, err
+		}
+		row.days = daysIn(to.Sub(row.start))
+	case c.props["DURATION"] != "":
+		v := c.props["DURATION"]
+		if row.days, err = parseICSDuration(v); err != nil {
+			return nil, fmt.Errorf("%s: %w", c.kind, err)
+		}
+	default:
+		row.days = 1
+	}
+	if row.days < 0 {
+		return nil, fmt.Errorf("%s: %s before DTSTART", c.kind, end)
+	}
+	if v := c.props["PERCENT-COMPLETE"]; v != "" {
+		row.progress, _ = strconv.Atoi(v)
+	}
+	return row, nil
+}
+
+// parseICSDuration returns the number of days in durations like
+// P5D, P2W or PT2H. Time parts are rounded up to a whole day so only
+// zero durations are milestones.
+func parseICSDuration(v string) (int, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(v, "+"), "P")
+	var t string
+	if i := strings.Index(s, "T"); i != -1 {
+		s, t = s[:i], s[i+1:]
+	}
+	days, err := sumICSDuration(s, "DW")
+	if err != nil {
+		return 0, fmt.Errorf("bad DURATION %q", v)
+	}
+	part, err := sumICSDuration(t, "HMS")
+	if err != nil {
+		return 0, fmt.Errorf("bad DURATION %q", v)
+	}
+	if part > 0 {
+		days++
+	}
+	return days, nil
+}
+
+// sumICSDuration returns the sum of numbers followed by one of the
+// given units, weeks are counted as 7 days.
+func sumICSDuration(s, units string) (int, error) {
+	var sum int
+	for s != "" {
+		i := strings.IndexAny(s, units)
+		if i < 1 {
+			return 0, fmt.Errorf("missing unit")
+		}
+		n, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return 0, err
+		}
+		if s[i] == 'W' {
+			n *= 7
+		}
+		sum += n
+		s = s[i+1:]
+	}
+	return sum, nil
+}
+
+// importRow is a task read from a file.
+type importRow struct {
+	n        int // row
+	name     string
+	start    time.Time
+	days     int
+	class    string
+	progress int
+	deps     []string // names with optional :SS, :FF or :SF suffix
+}
+
+// newImportedChart returns a chart spanning all the rows. Rows with
+// duplicate names or unknown dependencies are added to errs.
+func newImportedChart(rows []*importRow, errs ImportErrors) (*GanttChart, error) {
+	d := newGanttChart(time.Time{}, 0)
+	tasks := make(map[string]*Task)
+	unique := make([]*importRow, 0, len(rows))
+	for _, row := range rows {
+		if _, found := tasks[row.name]; found {
+			errs = append(errs, &RowError{
+				Row: row.n,
+				Err: fmt.Errorf("duplicate task %q", row.name),
+			})
+			continue
+		}
+		unique = append(unique, row)
+		var t *Task
+		if row.days == 0 {
+			t = d.Milestone(row.name)
+		} else {
+			t = d.Add(row.name)
+			t.class = row.class
+		}
+		t.Progress(row.progress)
+		tasks[row.name] = t
+	}
+	for _, row := range unique {
+		t := tasks[row.name]
+		adj := d.Place(t)
+		if !row.start.IsZero() {
+			adj.at(row.start, row.days)
+		}
+		for _, dep := range row.deps {
+			name, kind := splitDependency(dep)
+			parent, found := tasks[name]
+			if !found {
+				errs = append(errs, &RowError{
+					Row: row.n,
+					Err: fmt.Errorf("unknown dependency %q", name),
+				})
+				continue
+			}
+			adj.Depend(parent, kind, row.days)
+		}
+	}
+	// span all tasks
+	var from, to time.Time
+	for i, t := range d.tasks {
+		if i == 0 || t.from.Before(from) {
+			from = t.from
+		}
+		if t.to.After(to) {
+			to = t.to
+		}
+	}
+	d.start = from
+	d.days = daysIn(to.Sub(from)) + 1
+	if len(errs) > 0 {
+		return d, errs
+	}
+	return d, nil
+}
+
+// splitDependency returns the task name and kind of dependency.
+func splitDependency(v string) (string, Dependency) {
+	i := strings.LastIndex(v, ":")
+	if i == -1 {
+		return v, FinishToStart
+	}
+	kinds := map[string]Dependency{
+		"FS": FinishToStart,
+		"SS": StartToStart,
+		"FF": FinishToFinish,
+		"SF": StartToFinish,
+	}
+	kind, found := kinds[strings.ToUpper(v[i+1:])]
+	if !found {
+		return v, FinishToStart
+	}
+	return v[:i], kind
+}
+
+// RowError is a problem with one row, or component, of imported
+// data. Rows are numbered from 1, components by the line they begin
+// on.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %v: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error { return e.Err }
+
+// ImportErrors lists all problems found when importing tasks.
+type ImportErrors []*RowError
+
+func (e ImportErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,159 @@
+package design
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/types/date"
+)
+
+func TestLoadGanttChart_csv(t *testing.T) {
+	d, err := LoadGanttChart("testdata/project.csv")
+	assert := asserter.New(t)
+	assert(err == nil).Fatal(err)
+	assert().Equals(len(d.tasks), 5)
+	design, build, docs, test, rel := d.tasks[0], d.tasks[1], d.tasks[2], d.tasks[3], d.tasks[4]
+	assert().Equals(design.class, "span-blue")
+	assert().Equals(design.To(), date.String("20191115").Time())
+	assert().Equals(build.From(), date.String("20191115").Time())
+	assert().Equals(docs.From(), design.From())
+	assert().Equals(test.To(), build.To())
+	assert(rel.milestone).Error("zero duration is not a milestone")
+	assert().Equals(rel.From(), test.To())
+	assert().Equals(d.start, design.From())
+	assert().Contains(d.String(), `class="gantt-arrow"`)
+}
+
+func TestLoadGanttChart_ics(t *testing.T) {
+	d, err := LoadGanttChart("testdata/project.ics")
+	assert := asserter.New(t)
+	assert(err == nil).Fatal(err)
+	assert().Equals(len(d.tasks), 4)
+	build, test, rel := d.tasks[1], d.tasks[2], d.tasks[3]
+	assert().Equals(build.progress, 40)
+	// due in the afternoon of the 21st
+	assert().Equals(build.To(), date.String("20191122").Time())
+	assert().Equals(test.To(), date.String("20191125").Time())
+	assert(rel.milestone).Error("todo without start is not a milestone")
+	assert().Equals(rel.From(), date.String("20191125").Time())
+}
+
+func TestReadGanttCSV_errors(t *testing.T) {
+	data := `Design,2019-11-11,2019-11-32
+Build,20191111,5,green,Design
+Test,,3,,Nope
+,2019-11-11,3
+Lint,2019-11-11,
+Docs,2019-11-11,often
+Plan,,3
+Build,2019-11-12,2
+`
+	d, err := ReadGanttCSV(strings.NewReader(data))
+	var errs ImportErrors
+	if !errors.As(err, &errs) {
+		t.Fatal("expected ImportErrors, got", err)
+	}
+	got := err.Error()
+	for _, exp := range []string{
+		`row 1: bad end or duration "2019-11-32"`,
+		`row 3: unknown dependency "Nope"`,
+		"row 4: missing name",
+		"row 5: missing end or duration",
+		`row 6: bad end or duration "often"`,
+		"row 7: missing start",
+		`row 8: duplicate task "Build"`,
+	} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("missing %q in\n%s", exp, got)
+		}
+	}
+	// valid rows are still loaded
+	if len(d.tasks) != 2 {
+		t.Error("expected 2 tasks, got", len(d.tasks))
+	}
+}
+
+func TestReadGanttICS_errors(t *testing.T) {
+	data := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+SUMMARY:Bad
+DTSTART:2019
+END:VEVENT
+BEGIN:VTODO
+SUMMARY:Undated
+END:VTODO
+BEGIN:VEVENT
+SUMMARY:Long
+DTSTART:20191111
+DURATION:PXD
+END:VEVENT
+END:VCALENDAR
+`
+	_, err := ReadGanttICS(strings.NewReader(data))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	got := err.Error()
+	for _, exp := range []string{
+		"row 2: VEVENT: bad DTSTART",
+		"row 6: VTODO: missing DTSTART",
+		`row 9: VEVENT: bad DURATION "PXD"`,
+	} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("missing %q in\n%s", exp, got)
+		}
+	}
+	// folded lines are counted in the source
+	_, err = ReadGanttICS(strings.NewReader("BEGIN:VEVENT\nSUMMARY:Long\n  name\nbroken\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 4: missing colon") {
+		t.Error("expected error on line 4, got", err)
+	}
+	if _, err := LoadGanttChart("testdata/missing.ics"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestReadGanttICS_timed(t *testing.T) {
+	data := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+SUMMARY:Review
+DTSTART:20191111T090000Z
+DTEND:20191111T110000Z
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:Review
+DTSTART:20191112
+END:VEVENT
+END:VCALENDAR
+`
+	d, err := ReadGanttICS(strings.NewReader(data))
+	if err == nil || !strings.Contains(err.Error(), `row 7: duplicate task "Review"`) {
+		t.Error("expected duplicate error, got", err)
+	}
+	assert := asserter.New(t)
+	assert().Equals(len(d.tasks), 1)
+	review := d.tasks[0]
+	assert(!review.milestone).Error("same day event is a milestone")
+	assert().Equals(review.To(), date.String("20191112").Time())
+}
+
+func TestParseICSDuration(t *testing.T) {
+	ok := func(v string, exp int) {
+		t.Helper()
+		got, err := parseICSDuration(v)
+		if err != nil || got != exp {
+			t.Errorf("%s: got %v %v, expected %v", v, got, err, exp)
+		}
+	}
+	ok("P5D", 5)
+	ok("P2W", 14)
+	ok("P1DT12H", 2)
+	ok("PT3H", 1)
+	ok("PT0H", 0)
+	ok("P0D", 0)
+	if _, err := parseICSDuration("PT2X"); err == nil {
+		t.Error("expected error for bad time unit")
+	}
+}
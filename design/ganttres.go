@@ -0,0 +1,236 @@
+package design
+
+import (
+	"time"
+
+	"github.com/gregoryv/draw/shape"
+)
+
+// Assign assigns the task to the named resources, e.g. people.
+func (t *Task) Assign(resources ...string) *Task {
+	t.resources = append(t.resources, resources...)
+	return t
+}
+
+// Resources returns the resources the task is assigned to.
+func (t *Task) Resources() []string { return t.resources }
+
+// Resources returns all assigned resources in the order they first
+// appear.
+func (g *GanttChart) Resources() []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, t := range g.tasks {
+		for _, r := range t.resources {
+			if !seen[r] {
+				seen[r] = true
+				names = append(names, r)
+			}
+		}
+	}
+	return names
+}
+
+// Overallocation is a period when a resource is assigned more than
+// one task.
+type Overallocation struct {
+	Resource string
+	From, To time.Time
+	Tasks    []*Task
+}
+
+// Overallocations returns each period where two tasks of the same
+// resource overlap.
+func (g *GanttChart) Overallocations() []*Overallocation {
+	g.schedule()
+	res := make([]*Overallocation, 0)
+	for _, r := range g.Resources() {
+		tasks := g.assigned(r)
+		for i, a := range tasks {
+			for _, b := range tasks[i+1:] {
+				from, to := a.from, a.to
+				if b.from.After(from) {
+					from = b.from
+				}
+				if b.to.Before(to) {
+					to = b.to
+				}
+				if !from.Before(to) {
+					continue
+				}
+				res = append(res, &Overallocation{
+					Resource: r,
+					From:     from,
+					To:       to,
+					Tasks:    []*Task{a, b},
+				})
+			}
+		}
+	}
+	return res
+}
+
+// assigned returns tasks with a duration assigned to the resource.
+// Summaries and milestones are excluded.
+func (g *GanttChart) assigned(resource string) []*Task {
+	tasks := make([]*Task, 0)
+	for _, t := range g.tasks {
+		if t.milestone || len(t.children) > 0 {
+			continue
+		}
+		for _, r := range t.resources {
+			if r == resource {
+				tasks = append(tasks, t)
+				break
+			}
+		}
+	}
+	return tasks
+}
+
+// laneRows returns tasks grouped by resource, each group below a
+// lane row named as the resource. Unassigned tasks are listed last.
+func (g *GanttChart) laneRows() []*Task {
+	rows := make([]*Task, 0, len(g.tasks))
+	for _, r := range g.Resources() {
+		rows = append(rows, &Task{txt: r, lane: true})
+		rows = append(rows, g.assigned(r)...)
+	}
+	for _, t := range g.tasks {
+		if len(t.resources) == 0 && len(t.children) == 0 {
+			rows = append(rows, t)
+		}
+	}
+	return rows
+}
+
+// drawOverallocations highlights the overlapping part of bars for
+// each overallocation.
+func (d *GanttChart) drawOverallocations(line *timeline, rows []*Task, bars []*shape.Rect) {
+	for _, o := range d.Overallocations() {
+		x1, x2 := line.span(o.From, o.To)
+		for i, t := range rows {
+			if t != o.Tasks[0] && t != o.Tasks[1] {
+				continue
+			}
+			if d.GroupByResource && !d.inLane(rows, i, o.Resource) {
+				continue
+			}
+			over := shape.NewRect("")
+			over.SetClass("overallocated")
+			over.SetWidth(x2 - x1 - d.colSpace)
+			over.SetHeight(bars[i].Height())
+			d.Diagram.Place(over).At(x1, bars[i].Y)
+		}
+	}
+}
+
+// inLane returns true if row i is in the lane of the resource.
+func (d *GanttChart) inLane(rows []*Task, i int, resource string) bool {
+	for ; i >= 0; i-- {
+		if rows[i].lane {
+			return rows[i].txt == resource
+		}
+	}
+	return false
+}
+
+// utilisationHeight returns the height of the utilisation
+// histogram, zero if not shown.
+func (d *GanttChart) utilisationHeight() int {
+	if !d.ShowUtilisation {
+		return 0
+	}
+	n := len(d.Resources())
+	return d.Diagram.Font.LineHeight + n*(d.histHeight()+d.colSpace)
+}
+
+func (d *GanttChart) histHeight() int {
+	return 2 * d.Diagram.Font.Height
+}
+
+// drawUtilisation draws a histogram for each resource below the
+// tasks. Each column shows the assigned work of the resource
+// relative to the working time in that column, columns where the
+// resource is overallocated are highlighted.
+func (d *GanttChart) drawUtilisation(line *timeline) {
+	var (
+		scale = d.scale()
+		h     = d.histHeight()
+		y     = d.bottom() + d.Diagram.Font.LineHeight
+	)
+	for _, r := range d.Resources() {
+		label := shape.NewLabel(r)
+		d.Diagram.Place(label).At(d.padLeft, y+h-label.Height())
+		tasks := d.assigned(r)
+		for t := scale.truncate(d.start); t.Before(line.end); t = scale.next(t) {
+			load := d.utilisation(tasks, t, scale.next(t))
+			if load <= 0 {
+				continue
+			}
+			bar := shape.NewRect("")
+			bar.SetClass("gantt-load")
+			if load > 1 {
+				bar.SetClass("gantt-overload")
+				load = 1
+			}
+			x1, x2 := line.span(t, scale.next(t))
+			bh := int(float64(h) * load)
+			if bh < 1 {
+				bh = 1
+			}
+			bar.SetWidth(x2 - x1 - d.colSpace)
+			bar.SetHeight(bh)
+			d.Diagram.Place(bar).At(x1, y+h-bh)
+		}
+		base := shape.NewLine(d.padLeft, y+h, line.x(line.end), y+h)
+		base.SetClass("column-line")
+		d.Diagram.Place(base)
+		y += h + d.colSpace
+	}
+}
+
+// utilisation returns the assigned work of tasks relative to the
+// working time between from and to. Zero if there is no working
+// time.
+func (d *GanttChart) utilisation(tasks []*Task, from, to time.Time) float64 {
+	avail := d.workTime(from, to)
+	if avail == 0 {
+		return 0
+	}
+	var work time.Duration
+	for _, t := range tasks {
+		a, b := t.from, t.to
+		if from.After(a) {
+			a = from
+		}
+		if to.Before(b) {
+			b = to
+		}
+		if a.Before(b) {
+			work += d.workTime(a, b)
+		}
+	}
+	return float64(work) / float64(avail)
+}
+
+// workTime returns the duration between from and to that falls on
+// working days.
+func (d *GanttChart) workTime(from, to time.Time) time.Duration {
+	var sum time.Duration
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !d.Calendar.IsWorkday(day) {
+			continue
+		}
+		a, b := day, day.AddDate(0, 0, 1)
+		if from.After(a) {
+			a = from
+		}
+		if to.Before(b) {
+			b = to
+		}
+		sum += b.Sub(a)
+	}
+	return sum
+}
@@ -0,0 +1,83 @@
+package design
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+)
+
+func TestGanttChart_Overallocations(t *testing.T) {
+	var (
+		d    = NewGanttChart("20191111", 30)
+		spec = d.Add("Design").Assign("Anna")
+		code = d.Add("Build").Assign("John")
+		docs = d.Add("Document").Assign("Anna")
+		rel  = d.Milestone("Release").Assign("Anna")
+	)
+	d.Place(spec).At("20191111", 4)
+	d.Place(code).After(spec, 6)
+	d.Place(docs).At("20191113", 4)
+	d.Place(rel).After(docs, 0)
+
+	assert := asserter.New(t)
+	assert().Equals(d.Resources(), []string{"Anna", "John"})
+	got := d.Overallocations()
+	assert().Equals(len(got), 1)
+	o := got[0]
+	assert().Equals(o.Resource, "Anna")
+	assert().Equals(o.Tasks, []*Task{spec, docs})
+	assert().Equals(o.From, docs.From())
+	assert().Equals(o.To, spec.To())
+}
+
+func TestGanttChart_GroupByResource(t *testing.T) {
+	var (
+		d    = NewGanttChart("20191111", 30)
+		spec = d.Add("Design").Assign("Anna")
+		code = d.Add("Build").Assign("John", "Anna")
+		misc = d.Add("Misc")
+	)
+	d.GroupByResource = true
+	d.Place(spec).At("20191111", 4)
+	d.Place(code).After(spec, 6)
+	d.Place(misc).At("20191111", 2)
+
+	rows := d.rows()
+	var names []string
+	for _, r := range rows {
+		names = append(names, r.txt)
+	}
+	assert := asserter.New(t)
+	assert().Equals(strings.Join(names, ","), "Anna,Design,Build,John,Build,Misc")
+	assert().Equals(d.indent(rows[0]), 0)
+	assert().Equals(d.indent(rows[1]), 1)
+}
+
+func TestGanttChart_utilisation(t *testing.T) {
+	var (
+		d = NewGanttChart("20191111", 14)
+		a = d.Add("a").Assign("Anna")
+		b = d.Add("b").Assign("Anna")
+	)
+	d.Calendar = NewCalendar()
+	d.Place(a).At("20191111", 5)
+	d.Place(b).At("20191115", 2)
+
+	tasks := d.assigned("Anna")
+	assert := asserter.New(t)
+	day := func(v string) float64 {
+		from, _ := parseDay(v)
+		return d.utilisation(tasks, from, from.AddDate(0, 0, 1))
+	}
+	assert().Equals(day("20191111"), 1.0)
+	assert().Equals(day("20191115"), 2.0)
+	assert().Equals(day("20191116"), 0.0) // saturday
+	assert().Equals(day("20191118"), 1.0)
+	assert().Equals(day("20191119"), 0.0)
+
+	d.ShowUtilisation = true
+	out := d.String()
+	assert().Contains(out, "gantt-overload")
+	assert().Contains(out, "overallocated")
+}
@@ -0,0 +1,182 @@
+package design
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Scale is the time span of each column in a GanttChart.
+type Scale int
+
+const (
+	// ScaleDays shows one column per day, the default
+	ScaleDays Scale = iota
+	// ScaleHours shows one column per hour, for charts spanning a
+	// few days
+	ScaleHours
+	// ScaleWeeks shows one column per ISO week starting on Monday
+	ScaleWeeks
+	// ScaleMonths shows one column per calendar month
+	ScaleMonths
+	// ScaleQuarters shows one column per calendar quarter
+	ScaleQuarters
+	scaleYears // only used in headers
+)
+
+// unit returns the time spanned by one column of scales with
+// columns of fixed length.
+func (s Scale) unit() time.Duration {
+	switch s {
+	case ScaleHours:
+		return time.Hour
+	case ScaleWeeks:
+		return 7 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// columns returns the number of columns from a fixed origin to t,
+// including the part of the column containing t. Months, quarters
+// and years are counted in the calendar so borders don't drift.
+func (s Scale) columns(t time.Time) float64 {
+	switch s {
+	case ScaleMonths, ScaleQuarters, scaleYears:
+		from := s.truncate(t)
+		part := float64(t.Sub(from)) / float64(s.next(from).Sub(from))
+		y, m, _ := from.Date()
+		months := y*12 + int(m) - 1
+		switch s {
+		case ScaleMonths:
+			return float64(months) + part
+		case ScaleQuarters:
+			return float64(months/3) + part
+		default:
+			return float64(y) + part
+		}
+	default:
+		return float64(t.Unix()) / s.unit().Seconds()
+	}
+}
+
+// truncate returns the start of the column containing t.
+func (s Scale) truncate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	switch s {
+	case ScaleHours:
+		return t.Truncate(time.Hour)
+	case ScaleWeeks:
+		monday := (int(t.Weekday()) + 6) % 7
+		return time.Date(y, m, d-monday, 0, 0, 0, 0, t.Location())
+	case ScaleMonths:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	case ScaleQuarters:
+		q := (m-1)/3*3 + 1
+		return time.Date(y, q, 1, 0, 0, 0, 0, t.Location())
+	case scaleYears:
+		return time.Date(y, 1, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// next returns the start of the column following the one starting
+// at t.
+func (s Scale) next(t time.Time) time.Time {
+	switch s {
+	case ScaleHours:
+		return t.Add(time.Hour)
+	case ScaleWeeks:
+		return t.AddDate(0, 0, 7)
+	case ScaleMonths:
+		return t.AddDate(0, 1, 0)
+	case ScaleQuarters:
+		return t.AddDate(0, 3, 0)
+	case scaleYears:
+		return t.AddDate(1, 0, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// label returns the column label of the column starting at t.
+func (s Scale) label(t time.Time) string {
+	switch s {
+	case ScaleHours:
+		return fmt.Sprintf("%02v", t.Hour())
+	case ScaleWeeks:
+		_, week := t.ISOWeek()
+		return fmt.Sprintf("%02v", week)
+	case ScaleMonths:
+		return t.Month().String()[:3]
+	case ScaleQuarters:
+		return fmt.Sprintf("Q%v", (int(t.Month())-1)/3+1)
+	case scaleYears:
+		return fmt.Sprintf("%v", t.Year())
+	default:
+		return fmt.Sprintf("%02v", t.Day())
+	}
+}
+
+// title returns the header label of the period starting at t, short
+// returns a shorter version if possible.
+func (s Scale) title(t time.Time, short bool) string {
+	switch {
+	case s == ScaleDays && short:
+		return t.Format("2 Jan")
+	case s == ScaleDays:
+		return t.Format("2 Jan 2006")
+	case s == ScaleMonths && !short:
+		return t.Month().String()
+	}
+	return s.label(t)
+}
+
+// headers returns the periods shown in rows above the columns,
+// outermost first.
+func (s Scale) headers() []Scale {
+	switch s {
+	case ScaleHours:
+		return []Scale{ScaleDays}
+	case ScaleMonths:
+		return []Scale{scaleYears, ScaleQuarters}
+	case ScaleQuarters:
+		return []Scale{scaleYears}
+	default:
+		return []Scale{scaleYears, ScaleMonths}
+	}
+}
+
+// timeline maps time to x positions, proportional to the scale.
+type timeline struct {
+	start, end time.Time
+	left       int     // x of start
+	pitch      float64 // width of one column
+	scale      Scale
+}
+
+// x returns the position of t.
+func (l *timeline) x(t time.Time) int {
+	units := l.scale.columns(t) - l.scale.columns(l.start)
+	return l.left + int(math.Round(units*l.pitch))
+}
+
+// span returns the positions of from and to, limited to the visible
+// part of the timeline.
+func (l *timeline) span(from, to time.Time) (int, int) {
+	x1, x2 := l.x(from), l.x(to)
+	left, right := l.x(l.start), l.x(l.end)
+	if x1 < left {
+		x1 = left
+	}
+	if x2 > right {
+		x2 = right
+	}
+	return x1, x2
+}
+
+// contains returns true if t is within the visible part.
+func (l *timeline) contains(t time.Time) bool {
+	return !t.Before(l.start) && t.Before(l.end)
+}
@@ -0,0 +1,126 @@
+package design
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/types/date"
+)
+
+func TestScale(t *testing.T) {
+	at := time.Date(2019, 11, 14, 15, 30, 0, 0, time.UTC) // Thursday
+	ok := func(s Scale, start, next, label string) {
+		t.Helper()
+		layout := "2006-01-02 15:04"
+		got := s.truncate(at)
+		if v := got.Format(layout); v != start {
+			t.Errorf("%v truncate: got %s, expected %s", s, v, start)
+		}
+		if v := s.next(got).Format(layout); v != next {
+			t.Errorf("%v next: got %s, expected %s", s, v, next)
+		}
+		if v := s.label(got); v != label {
+			t.Errorf("%v label: got %s, expected %s", s, v, label)
+		}
+	}
+	ok(ScaleHours, "2019-11-14 15:00", "2019-11-14 16:00", "15")
+	ok(ScaleDays, "2019-11-14 00:00", "2019-11-15 00:00", "14")
+	ok(ScaleWeeks, "2019-11-11 00:00", "2019-11-18 00:00", "46")
+	ok(ScaleMonths, "2019-11-01 00:00", "2019-12-01 00:00", "Nov")
+	ok(ScaleQuarters, "2019-10-01 00:00", "2020-01-01 00:00", "Q4")
+	ok(scaleYears, "2019-01-01 00:00", "2020-01-01 00:00", "2019")
+}
+
+func TestTimeline(t *testing.T) {
+	start := date.String("20191111").Time()
+	l := &timeline{
+		start: start,
+		end:   start.AddDate(0, 0, 10),
+		left:  100,
+		pitch: 15,
+		scale: ScaleDays,
+	}
+	assert := asserter.New(t)
+	assert().Equals(l.x(start.Add(12*time.Hour)), 108)
+	x1, x2 := l.span(start.AddDate(0, 0, -2), start.AddDate(0, 0, 20))
+	assert().Equals(x1, 100)
+	assert().Equals(x2, 250)
+	assert(!l.contains(l.end)).Error("end is not visible")
+
+	// months of different length are equally wide
+	l.start = date.String("20190101").Time()
+	l.end = l.start.AddDate(1, 0, 0)
+	l.scale = ScaleMonths
+	for m := 1; m <= 12; m++ {
+		assert().Equals(l.x(l.start.AddDate(0, m, 0)), 100+15*m)
+	}
+	assert().Equals(l.x(date.String("20190216").Time()), 123)
+	l.scale = ScaleQuarters
+	assert().Equals(l.x(date.String("20191001").Time()), 145)
+}
+
+func TestGanttChart_Scale(t *testing.T) {
+	ok := func(s Scale, days int, exp ...string) {
+		t.Helper()
+		d := NewGanttChart("20191111", days)
+		d.Scale = s
+		dev := d.Add("Develop")
+		d.Place(dev).At("20191111", 30)
+		got := d.String()
+		for _, v := range exp {
+			if !strings.Contains(got, ">"+v+"</text>") {
+				t.Errorf("%v: missing %q", s, v)
+			}
+		}
+		if got := d.Inline(); strings.Contains(got, "class") {
+			t.Error("found class attributes\n", got)
+		}
+	}
+	ok(ScaleHours, 2, "11 Nov 2019", "00", "23")
+	ok(ScaleDays, 30, "2019", "November", "December", "11")
+	ok(ScaleWeeks, 90, "2019", "2020", "46")
+	ok(ScaleMonths, 365, "2019", "Q4", "Nov", "Jan")
+	ok(ScaleQuarters, 730, "2020", "Q4", "Q1")
+}
+
+func TestGanttChart_Mark(t *testing.T) {
+	d := NewGanttChart("20191111", 10)
+	d.Mark = date.String("20191112").Time().Add(12 * time.Hour)
+	got := d.String()
+	if !strings.Contains(got, `class="mark"`) {
+		t.Fatal("missing mark\n", got)
+	}
+	// halfway between the 12th and 13th
+	if !strings.Contains(got, `x1="55" y1`) {
+		t.Error("mark not at exact time\n", got)
+	}
+}
+
+func TestGanttChart_AtTime(t *testing.T) {
+	d := NewGanttChart("20191111", 1)
+	d.Scale = ScaleHours
+	day := d.Add("Day")
+	d.Place(day).At("20191111", 1)
+	fix := d.Add("Fix")
+	from := date.String("20191111").Time().Add(9*time.Hour + 30*time.Minute)
+	d.Place(fix).AtTime(from, 90*time.Minute)
+
+	assert := asserter.New(t)
+	assert().Equals(fix.To(), from.Add(90*time.Minute))
+	got := d.String()
+	bars := regexp.MustCompile(
+		`class="span-green" x="(\d+)" y="\d+" width="(\d+)"`,
+	).FindAllStringSubmatch(got, -1)
+	assert().Equals(len(bars), 2)
+	atoi := func(v string) float64 { i, _ := strconv.Atoi(v); return float64(i) }
+	// a day spans 24 columns
+	left, pitch := atoi(bars[0][1]), (atoi(bars[0][2])+float64(d.colSpace))/24
+	x1, x2 := left+math.Round(9.5*pitch), left+11*pitch
+	assert().Equals(atoi(bars[1][1]), x1)
+	assert().Equals(atoi(bars[1][2]), x2-x1-float64(d.colSpace))
+}
@@ -0,0 +1,108 @@
+package design
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// icsComponent is a VEVENT, VTODO or other component of iCalendar
+// data with its properties. Property parameters are dropped.
+type icsComponent struct {
+	kind  string
+	line  int // of BEGIN in the source
+	props map[string]string
+}
+
+// date returns the named date or date-time property.
+func (c *icsComponent) date(name string) (time.Time, error) {
+	v := c.props[name]
+	if len(v) < 8 {
+		return time.Time{}, fmt.Errorf("%s: bad %s %q", c.kind, name, v)
+	}
+	return parseDay(v[:8]) // time of day is ignored
+}
+
+// endDay returns the day after the last day covered by the named
+// end property. A time of day, other than midnight, covers the day
+// it's on.
+func (c *icsComponent) endDay(name string) (time.Time, error) {
+	to, err := c.date(name)
+	if err != nil {
+		return to, err
+	}
+	v := c.props[name]
+	if len(v) > 9 && v[8] == 'T' && strings.Trim(v[9:], "0Z") != "" {
+		to = to.AddDate(0, 0, 1)
+	}
+	return to, nil
+}
+
+// readICS returns the components of the iCalendar data, e.g. events
+// and todos but not the surrounding VCALENDAR.
+func readICS(r io.Reader) ([]*icsComponent, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*icsComponent, 0)
+	stack := make([]*icsComponent, 0) // nested components, e.g. VALARM
+	for _, line := range lines {
+		colon := strings.Index(line.text, ":")
+		if colon == -1 {
+			return nil, fmt.Errorf("line %v: missing colon", line.n)
+		}
+		name, value := line.text[:colon], line.text[colon+1:]
+		if semi := strings.Index(name, ";"); semi != -1 {
+			name = name[:semi]
+		}
+		name = strings.ToUpper(name)
+		switch {
+		case name == "BEGIN":
+			stack = append(stack, &icsComponent{
+				kind:  strings.ToUpper(value),
+				line:  line.n,
+				props: make(map[string]string),
+			})
+		case len(stack) == 0:
+			// ignore properties outside components
+		case name == "END":
+			c := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if c.kind != "VCALENDAR" {
+				result = append(result, c)
+			}
+		default:
+			stack[len(stack)-1].props[name] = value
+		}
+	}
+	return result, nil
+}
+
+// icsLine is a logical line of iCalendar data.
+type icsLine struct {
+	n    int // source line it starts on
+	text string
+}
+
+// unfoldICS returns the logical lines of iCalendar data where long
+// lines are folded by starting the continuation with a space or tab.
+func unfoldICS(r io.Reader) ([]icsLine, error) {
+	lines := make([]icsLine, 0)
+	s := bufio.NewScanner(r)
+	var n int
+	for s.Scan() {
+		n++
+		line := strings.TrimRight(s.Text(), "\r")
+		switch {
+		case line == "":
+		case (line[0] == ' ' || line[0] == '\t') && len(lines) > 0:
+			lines[len(lines)-1].text += line[1:]
+		default:
+			lines = append(lines, icsLine{n: n, text: line})
+		}
+	}
+	return lines, s.Err()
+}
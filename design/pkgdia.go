@@ -0,0 +1,449 @@
+package design
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gregoryv/draw"
+	"github.com/gregoryv/draw/shape"
+)
+
+// NewPackageDiagram returns a diagram showing import relations
+// between packages. Use Load to read packages of a module from disk.
+func NewPackageDiagram() *PackageDiagram {
+	return &PackageDiagram{
+		Diagram: NewDiagram(),
+		imports: make(map[string][]string),
+	}
+}
+
+// PackageDiagram draws one component per package and an arrow for
+// each import. Packages are laid out in rows where importing
+// packages are placed above the packages they import.
+type PackageDiagram struct {
+	*Diagram
+
+	// Module is the module path as found in go.mod, set by Load
+	Module string
+
+	imports      map[string][]string
+	hideStd      bool
+	hideExternal bool
+	collapse     []string
+}
+
+// Load reads go.mod in the given directory and parses the imports of
+// all non test go files in the module. Nested modules, testdata and
+// vendor directories are skipped.
+func (d *PackageDiagram) Load(dir string) error {
+	gomod, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return err
+	}
+	d.Module, err = modulePath(gomod)
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if p != dir && skipDir(p) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isSourceFile(info.Name()) {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, p, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+		pkg := path.Join(d.Module, filepath.ToSlash(rel))
+		imports := make([]string, 0, len(f.Imports))
+		for _, imp := range f.Imports {
+			v, _ := strconv.Unquote(imp.Path.Value)
+			if v == "C" {
+				continue
+			}
+			imports = append(imports, v)
+		}
+		d.Import(pkg, imports...)
+		return nil
+	})
+}
+
+// skipDir returns true for directories that never hold packages of
+// the module being loaded.
+func skipDir(dir string) bool {
+	name := filepath.Base(dir)
+	switch {
+	case name == "testdata", name == "vendor":
+		return true
+	case strings.HasPrefix(name, "."), strings.HasPrefix(name, "_"):
+		return true
+	}
+	_, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil // nested module
+}
+
+func isSourceFile(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+}
+
+// modulePath returns the module path declared in the given go.mod
+// content.
+func modulePath(gomod []byte) (string, error) {
+	s := bufio.NewScanner(bytes.NewReader(gomod))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	return "", fmt.Errorf("missing module directive in go.mod")
+}
+
+// Import adds pkg with the given imports to the diagram. Imports
+// already added are ignored.
+func (d *PackageDiagram) Import(pkg string, imports ...string) {
+	current, found := d.imports[pkg]
+	if !found {
+		current = make([]string, 0)
+	}
+	for _, imp := range imports {
+		if !contains(current, imp) {
+			current = append(current, imp)
+		}
+	}
+	d.imports[pkg] = current
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// HideStd hides packages from the standard library.
+func (d *PackageDiagram) HideStd() { d.hideStd = true }
+
+// HideExternal hides packages that are neither in the standard
+// library nor in the loaded module.
+func (d *PackageDiagram) HideExternal() { d.hideExternal = true }
+
+// Collapse shows all packages starting with any of the given
+// prefixes as one package named by the prefix.
+func (d *PackageDiagram) Collapse(prefix ...string) {
+	d.collapse = append(d.collapse, prefix...)
+}
+
+// isStd returns true if pkg looks like a standard library package,
+// ie. it's not in the loaded module and the first path element has
+// no dot.
+func (d *PackageDiagram) isStd(pkg string) bool {
+	if d.inModule(pkg) {
+		return false
+	}
+	first := strings.SplitN(pkg, "/", 2)[0]
+	return !strings.Contains(first, ".")
+}
+
+// inModule returns true if pkg is in the loaded module.
+func (d *PackageDiagram) inModule(pkg string) bool {
+	return d.Module != "" &&
+		(pkg == d.Module || strings.HasPrefix(pkg, d.Module+"/"))
+}
+
+func (d *PackageDiagram) isExternal(pkg string) bool {
+	if d.isStd(pkg) {
+		return false
+	}
+	if d.Module == "" {
+		_, found := d.imports[pkg]
+		return !found
+	}
+	return !d.inModule(pkg)
+}
+
+func (d *PackageDiagram) visible(pkg string) bool {
+	if d.hideStd && d.isStd(pkg) {
+		return false
+	}
+	if d.hideExternal && d.isExternal(pkg) {
+		return false
+	}
+	return true
+}
+
+// name returns the name pkg is shown as, which differs only for
+// collapsed packages.
+func (d *PackageDiagram) name(pkg string) string {
+	for _, prefix := range d.collapse {
+		if pkg == prefix || strings.HasPrefix(pkg, prefix+"/") {
+			return prefix
+		}
+	}
+	return pkg
+}
+
+// Edges returns visible import edges as from, to pairs after filters
+// and collapsing is applied, sorted by from and to.
+func (d *PackageDiagram) Edges() [][2]string {
+	seen := make(map[[2]string]bool)
+	edges := make([][2]string, 0)
+	for pkg, imports := range d.imports {
+		if !d.visible(pkg) {
+			continue
+		}
+		from := d.name(pkg)
+		for _, imp := range imports {
+			if !d.visible(imp) {
+				continue
+			}
+			e := [2]string{from, d.name(imp)}
+			if e[0] == e[1] || seen[e] {
+				continue
+			}
+			seen[e] = true
+			edges = append(edges, e)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i][0] == edges[j][0] {
+			return edges[i][1] < edges[j][1]
+		}
+		return edges[i][0] < edges[j][0]
+	})
+	return edges
+}
+
+// Packages returns the sorted names of all visible packages.
+func (d *PackageDiagram) Packages() []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	add := func(pkg string) {
+		if !d.visible(pkg) {
+			return
+		}
+		name := d.name(pkg)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for pkg, imports := range d.imports {
+		add(pkg)
+		for _, imp := range imports {
+			add(imp)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Cycles returns groups of visible packages that import each other,
+// directly or indirectly.
+func (d *PackageDiagram) Cycles() [][]string {
+	cycles := make([][]string, 0)
+	for _, c := range components(d.Packages(), d.Edges()) {
+		if len(c) > 1 {
+			cycles = append(cycles, c)
+		}
+	}
+	return cycles
+}
+
+// components returns the strongly connected components of the
+// graph using Tarjan's algorithm. Each component is sorted.
+func components(nodes []string, edges [][2]string) [][]string {
+	out := make(map[string][]string)
+	for _, e := range edges {
+		out[e[0]] = append(out[e[0]], e[1])
+	}
+	var (
+		index   = make(map[string]int)
+		low     = make(map[string]int)
+		onStack = make(map[string]bool)
+		stack   = make([]string, 0)
+		result  = make([][]string, 0)
+		next    int
+		visit   func(n string)
+	)
+	visit = func(n string) {
+		index[n] = next
+		low[n] = next
+		next++
+		stack = append(stack, n)
+		onStack[n] = true
+		for _, m := range out[n] {
+			if _, found := index[m]; !found {
+				visit(m)
+				if low[m] < low[n] {
+					low[n] = low[m]
+				}
+			} else if onStack[m] && index[m] < low[n] {
+				low[n] = index[m]
+			}
+		}
+		if low[n] != index[n] {
+			return
+		}
+		c := make([]string, 0)
+		for {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[m] = false
+			c = append(c, m)
+			if m == n {
+				break
+			}
+		}
+		sort.Strings(c)
+		result = append(result, c)
+	}
+	for _, n := range nodes {
+		if _, found := index[n]; !found {
+			visit(n)
+		}
+	}
+	return result
+}
+
+// layers returns packages grouped by rows, first row holds packages
+// not imported by any other package. Packages in a cycle share the
+// same row.
+func layers(nodes []string, edges [][2]string) [][]string {
+	comps := components(nodes, edges)
+	compOf := make(map[string]int)
+	for i, c := range comps {
+		for _, n := range c {
+			compOf[n] = i
+		}
+	}
+	// depth of a component is the longest path to a component
+	// without imports. Tarjan returns components in reverse
+	// topological order, ie. imported components first.
+	depth := make([]int, len(comps))
+	for i, c := range comps {
+		for _, n := range c {
+			for _, e := range edges {
+				if e[0] != n || compOf[e[1]] == i {
+					continue
+				}
+				if v := depth[compOf[e[1]]] + 1; v > depth[i] {
+					depth[i] = v
+				}
+			}
+		}
+	}
+	var max int
+	for _, v := range depth {
+		if v > max {
+			max = v
+		}
+	}
+	rows := make([][]string, max+1)
+	for i, c := range comps {
+		row := max - depth[i]
+		rows[row] = append(rows[row], c...)
+	}
+	for _, row := range rows {
+		sort.Strings(row)
+	}
+	return rows
+}
+
+// WriteSVG renders the diagram as SVG to the given writer.
+func (d *PackageDiagram) WriteSVG(w io.Writer) error {
+	var (
+		nodes = d.Packages()
+		edges = d.Edges()
+		rows  = layers(nodes, edges)
+		cycle = make(map[string]int) // package to cycle index
+	)
+	for i, c := range d.Cycles() {
+		for _, n := range c {
+			cycle[n] = i + 1
+		}
+	}
+	boxes := make(map[string]*shape.Component)
+	widths := make([]int, len(rows))
+	var widest int
+	for i, row := range rows {
+		for j, pkg := range row {
+			c := shape.NewComponent(pkg)
+			switch {
+			case cycle[pkg] > 0:
+				c.SetClass("cycle")
+			case d.isStd(pkg) || d.isExternal(pkg):
+				c.SetClass("external")
+			}
+			d.applyStyle(c)
+			boxes[pkg] = c
+			if j > 0 {
+				widths[i] += d.Spacing
+			}
+			widths[i] += c.Width()
+		}
+		if widths[i] > widest {
+			widest = widths[i]
+		}
+	}
+	// place rows centered below each other
+	y := d.Pad.Top
+	for i, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		x := d.Pad.Left + (widest-widths[i])/2
+		for _, pkg := range row {
+			c := boxes[pkg]
+			d.Place(c).At(x, y)
+			x += c.Width() + d.Spacing
+		}
+		y += boxes[row[0]].Height() + 2*d.Spacing
+	}
+	for _, e := range edges {
+		arrow := shape.NewArrowBetween(boxes[e[0]], boxes[e[1]])
+		if cycle[e[0]] > 0 && cycle[e[0]] == cycle[e[1]] {
+			arrow.SetClass("cycle-arrow")
+		}
+		d.Place(arrow)
+	}
+	return d.Diagram.WriteSVG(w)
+}
+
+// SaveAs saves the diagram to filename as SVG
+func (d *PackageDiagram) SaveAs(filename string) error {
+	return saveAs(d, d.Style, filename)
+}
+
+// Inline returns rendered SVG with inlined style
+func (d *PackageDiagram) Inline() string {
+	return draw.Inline(d, d.Style)
+}
+
+// String returns rendered SVG
+func (d *PackageDiagram) String() string { return toString(d) }
@@ -0,0 +1,103 @@
+package design
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+)
+
+func TestPackageDiagram_Load(t *testing.T) {
+	d := NewPackageDiagram()
+	err := d.Load("..")
+	assert := asserter.New(t)
+	assert(err == nil).Fatal(err)
+	assert().Equals(d.Module, "github.com/gregoryv/draw")
+
+	pkgs := strings.Join(d.Packages(), " ")
+	assert().Contains(pkgs, "github.com/gregoryv/draw/design")
+	assert().Contains(pkgs, "github.com/gregoryv/draw/shape")
+	assert().Contains(pkgs, "fmt")
+	assert(!strings.Contains(pkgs, "testing")).Error("test imports included")
+
+	d.HideStd()
+	d.HideExternal()
+	for _, pkg := range d.Packages() {
+		assert(strings.HasPrefix(pkg, d.Module)).Error("visible: ", pkg)
+	}
+}
+
+func TestPackageDiagram_dotless(t *testing.T) {
+	d := NewPackageDiagram()
+	d.Module = "myapp"
+	d.Import("myapp", "fmt", "myapp/store")
+	d.Import("myapp/store", "database/sql", "github.com/lib/pq")
+	d.HideStd()
+	assert := asserter.New(t)
+	assert().Equals(d.Packages(), []string{
+		"github.com/lib/pq", "myapp", "myapp/store",
+	})
+	d.HideExternal()
+	assert().Equals(d.Packages(), []string{"myapp", "myapp/store"})
+}
+
+func TestPackageDiagram_Load_errors(t *testing.T) {
+	d := NewPackageDiagram()
+	if err := d.Load("/no/such/dir"); err == nil {
+		t.Error("expected error for missing go.mod")
+	}
+	if err := d.Load("testdata"); err == nil {
+		t.Error("expected error for missing go.mod")
+	}
+	if _, err := modulePath([]byte("go 1.16")); err == nil {
+		t.Error("expected error for missing module directive")
+	}
+}
+
+func TestPackageDiagram_Collapse(t *testing.T) {
+	d := NewPackageDiagram()
+	d.Import("x.com/a", "x.com/b/one", "x.com/b/two")
+	d.Import("x.com/b/one", "x.com/b/two")
+	d.Collapse("x.com/b")
+	assert := asserter.New(t)
+	assert().Equals(d.Packages(), []string{"x.com/a", "x.com/b"})
+	assert().Equals(d.Edges(), [][2]string{{"x.com/a", "x.com/b"}})
+}
+
+func TestPackageDiagram_Cycles(t *testing.T) {
+	d := NewPackageDiagram()
+	d.Import("x.com/a", "x.com/b")
+	d.Import("x.com/b", "x.com/c")
+	d.Import("x.com/c", "x.com/a", "x.com/d")
+	d.Import("x.com/d")
+	assert := asserter.New(t)
+	assert().Equals(d.Cycles(), [][]string{{"x.com/a", "x.com/b", "x.com/c"}})
+
+	rows := layers(d.Packages(), d.Edges())
+	assert().Equals(rows, [][]string{
+		{"x.com/a", "x.com/b", "x.com/c"},
+		{"x.com/d"},
+	})
+	got := d.String()
+	assert().Contains(got, `class="cycle"`)
+	assert().Contains(got, `class="cycle-arrow"`)
+}
+
+func TestPackageDiagram_Inline(t *testing.T) {
+	d := NewPackageDiagram()
+	d.Import("x.com/a", "x.com/b", "fmt")
+	d.Import("x.com/b", "x.com/a", "y.org/ext")
+	d.SetCaption("Figure 1. Imports")
+	got := d.Inline()
+	if strings.Contains(got, "class") {
+		t.Error("found class attributes\n", got)
+	}
+}
+
+func TestPackageDiagram_empty(t *testing.T) {
+	d := NewPackageDiagram()
+	got := d.String()
+	if !strings.Contains(got, "<svg") {
+		t.Error(got)
+	}
+}
@@ -0,0 +1,494 @@
+package design
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gregoryv/draw"
+	"github.com/gregoryv/draw/shape"
+)
+
+// NewStateDiagram returns an empty state machine diagram.
+func NewStateDiagram() *StateDiagram {
+	return &StateDiagram{
+		Diagram: NewDiagram(),
+		root:    &State{},
+	}
+}
+
+// StateDiagram shows states and transitions between them. States
+// are placed automatically in rows following the transitions from
+// the initial state.
+type StateDiagram struct {
+	*Diagram
+
+	root        *State
+	transitions []*Transition
+}
+
+// State adds a top level state with the given name.
+func (d *StateDiagram) State(name string) *State {
+	return d.root.State(name)
+}
+
+// Initial adds an initial pseudo state with a transition to s. The
+// pseudo state is placed in the same scope as s.
+func (d *StateDiagram) Initial(s *State) *Transition {
+	start := s.parent.add(&State{kind: initialState})
+	return d.Transition(start, s, "")
+}
+
+// Final adds a final pseudo state with a transition from s. The
+// pseudo state is placed in the same scope as s.
+func (d *StateDiagram) Final(s *State, event string) *Transition {
+	end := s.parent.add(&State{kind: finalState})
+	return d.Transition(s, end, event)
+}
+
+// Transition adds a transition between the two states triggered by
+// event. Set Guard and Action of the returned transition for the
+// full label.
+func (d *StateDiagram) Transition(from, to *State, event string) *Transition {
+	t := &Transition{
+		From:  from,
+		To:    to,
+		Event: event,
+	}
+	d.transitions = append(d.transitions, t)
+	return t
+}
+
+// Find returns the first state with the given name, searching
+// composite states as well. Returns nil if not found.
+func (d *StateDiagram) Find(name string) *State {
+	return d.root.find(name)
+}
+
+type stateKind int
+
+const (
+	simpleState stateKind = iota
+	initialState
+	finalState
+)
+
+// State is one state in a StateDiagram. A state with sub states is
+// a composite state.
+type State struct {
+	Name string
+
+	kind     stateKind
+	parent   *State
+	children []*State
+	shape    shape.Shape
+}
+
+// State adds a sub state, making s a composite state.
+func (s *State) State(name string) *State {
+	return s.add(&State{Name: name})
+}
+
+func (s *State) add(c *State) *State {
+	c.parent = s
+	s.children = append(s.children, c)
+	return c
+}
+
+// IsComposite returns true if s has sub states.
+func (s *State) IsComposite() bool { return len(s.children) > 0 }
+
+func (s *State) find(name string) *State {
+	for _, c := range s.children {
+		if c.kind == simpleState && c.Name == name {
+			return c
+		}
+		if found := c.find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Transition between two states with a label in the form
+// event [guard] / action.
+type Transition struct {
+	From, To *State
+
+	Event  string
+	Guard  string
+	Action string
+}
+
+// Label returns the transition text, empty parts are left out.
+func (t *Transition) Label() string {
+	var parts []string
+	if t.Event != "" {
+		parts = append(parts, t.Event)
+	}
+	if t.Guard != "" {
+		parts = append(parts, "["+t.Guard+"]")
+	}
+	if t.Action != "" {
+		parts = append(parts, "/ "+t.Action)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (t *Transition) toSelf() bool { return t.From == t.To }
+
+// WriteSVG renders the diagram as SVG to the given writer.
+func (d *StateDiagram) WriteSVG(w io.Writer) error {
+	d.layout(d.root, d.Pad.Left, d.Pad.Top)
+	d.placeStates(d.root)
+	for _, t := range d.transitions {
+		if t.toSelf() {
+			d.placeLoop(t)
+			continue
+		}
+		lnk, _ := d.Diagram.Link(t.From.shape, t.To.shape, t.Label())
+		if d.hasReverse(t) {
+			d.separate(lnk)
+		}
+	}
+	return d.Diagram.WriteSVG(w)
+}
+
+// hasReverse returns true if there is a transition in the opposite
+// direction of t.
+func (d *StateDiagram) hasReverse(t *Transition) bool {
+	for _, r := range d.transitions {
+		if r.From == t.To && r.To == t.From {
+			return true
+		}
+	}
+	return false
+}
+
+// separate moves the arrow sideways so it doesn't overlap the arrow
+// going in the opposite direction. Labels of the two arrows are kept
+// apart when placed.
+func (d *StateDiagram) separate(lnk *shape.Arrow) {
+	gap := 5
+	dir := lnk.Direction()
+	vertical := dir.Is(shape.DirectionUp) || dir.Is(shape.DirectionDown)
+	back := dir.Is(shape.DirectionUp) || dir == shape.DirectionLeft
+	switch {
+	case vertical && back:
+		shape.Move(lnk, -gap, 0)
+	case vertical:
+		shape.Move(lnk, gap, 0)
+	case back:
+		shape.Move(lnk, 0, gap)
+	default:
+		shape.Move(lnk, 0, -gap)
+	}
+}
+
+// placeStates adds the shapes of all sub states, composite states
+// before their children so they are drawn behind.
+func (d *StateDiagram) placeStates(scope *State) {
+	for _, c := range scope.children {
+		d.Diagram.Place(c.shape)
+		d.placeStates(c)
+	}
+}
+
+// placeLoop draws a self transition as a loop on the right side of
+// the state.
+func (d *StateDiagram) placeLoop(t *Transition) {
+	x, y := t.From.shape.Position()
+	x += t.From.shape.Width()
+	y += t.From.shape.Height() / 2
+	gap := d.Font.LineHeight / 2
+	margin := 15
+	l1 := shape.NewLine(x, y-gap, x+margin, y-gap)
+	l2 := shape.NewLine(x+margin, y-gap, x+margin, y+gap)
+	arrow := shape.NewArrow(x+margin, y+gap, x, y+gap)
+	d.Diagram.Place(l1, l2, arrow)
+	if txt := t.Label(); txt != "" {
+		label := shape.NewLabel(txt)
+		d.Diagram.Place(label).At(x+margin+d.TextPad.Left, y-label.Height()/2-d.TextPad.Top)
+	}
+}
+
+// loopWidth returns the extra width needed on the right side of s
+// for self transitions.
+func (d *StateDiagram) loopWidth(s *State) int {
+	var w int
+	for _, t := range d.transitions {
+		if t.From == s && t.toSelf() {
+			v := 15 + d.TextPad.Left + d.Font.TextWidth(t.Label())
+			if v > w {
+				w = v
+			}
+		}
+	}
+	return w
+}
+
+// layout positions all sub states of scope starting at x,y and
+// returns the width and height used. Composite sub states are laid
+// out once, when their shape is created, and then moved into place.
+func (d *StateDiagram) layout(scope *State, x, y int) (int, int) {
+	var (
+		rows   = d.rows(scope)
+		hspace = 2 * d.Spacing
+		vspace = d.Spacing + d.Font.LineHeight
+		widths = make([]int, len(rows))
+		widest int
+	)
+	for _, c := range scope.children {
+		d.newShape(c)
+	}
+	for i, row := range rows {
+		for j, c := range row {
+			if j > 0 {
+				widths[i] += hspace
+			}
+			widths[i] += c.shape.Width() + d.loopWidth(c)
+		}
+		if widths[i] > widest {
+			widest = widths[i]
+		}
+	}
+	height := 0
+	for i, row := range rows {
+		cx := x + (widest-widths[i])/2
+		rowHeight := 0
+		for _, c := range row {
+			if h := c.shape.Height(); h > rowHeight {
+				rowHeight = h
+			}
+		}
+		for _, c := range row {
+			cy := y + height + (rowHeight-c.shape.Height())/2
+			ox, oy := c.shape.Position()
+			moveState(c, cx-ox, cy-oy)
+			cx += c.shape.Width() + d.loopWidth(c) + hspace
+		}
+		height += rowHeight
+		if i < len(rows)-1 {
+			height += vspace
+		}
+	}
+	if scope.IsComposite() && scope != d.root {
+		r := scope.shape.(*shape.Rect)
+		top := d.Font.LineHeight + d.TextPad.Top + d.TextPad.Bottom
+		r.SetX(x)
+		r.SetY(y)
+		r.SetWidth(widest + d.Pad.Left + d.Pad.Right)
+		r.SetHeight(height + top + d.Pad.Bottom)
+		// children were placed at x,y, move them inside the rect
+		for _, c := range scope.children {
+			moveState(c, d.Pad.Left, top)
+		}
+		return r.Width(), r.Height()
+	}
+	return widest, height
+}
+
+func moveState(s *State, dx, dy int) {
+	shape.Move(s.shape, dx, dy)
+	for _, c := range s.children {
+		moveState(c, dx, dy)
+	}
+}
+
+// newShape creates the shape for s, composite states are sized by
+// laying out their children at the origin.
+func (d *StateDiagram) newShape(s *State) {
+	switch {
+	case s.kind == initialState:
+		s.shape = shape.NewDot()
+	case s.kind == finalState:
+		s.shape = shape.NewExitDot()
+	case s.IsComposite():
+		r := shape.NewRect(s.Name)
+		r.SetClass("composite")
+		s.shape = r
+		d.layout(s, 0, 0)
+	default:
+		s.shape = shape.NewState(s.Name)
+	}
+	d.applyStyle(s.shape)
+}
+
+// rows groups the sub states of scope by distance from the initial
+// state following transitions within the scope. States that cannot
+// be reached are added in declaration order.
+func (d *StateDiagram) rows(scope *State) [][]*State {
+	next := make(map[*State][]*State)
+	for _, t := range d.transitions {
+		from, to := childOf(scope, t.From), childOf(scope, t.To)
+		if from != nil && to != nil && from != to {
+			next[from] = append(next[from], to)
+		}
+	}
+	level := make(map[*State]int)
+	queue := make([]*State, 0)
+	for _, c := range scope.children {
+		if c.kind == initialState {
+			level[c] = 0
+			queue = append(queue, c)
+		}
+	}
+	rows := make([][]*State, 0)
+	walk := func() {
+		for len(queue) > 0 {
+			s := queue[0]
+			queue = queue[1:]
+			for len(rows) <= level[s] {
+				rows = append(rows, nil)
+			}
+			rows[level[s]] = append(rows[level[s]], s)
+			for _, n := range next[s] {
+				if _, found := level[n]; !found {
+					level[n] = level[s] + 1
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	walk()
+	// unreachable states start new rows below the others
+	for _, c := range scope.children {
+		if _, found := level[c]; !found {
+			level[c] = len(rows)
+			queue = append(queue, c)
+			walk()
+		}
+	}
+	return rows
+}
+
+// childOf returns the direct child of scope that is s or holds s.
+func childOf(scope, s *State) *State {
+	for ; s != nil; s = s.parent {
+		if s.parent == scope {
+			return s
+		}
+	}
+	return nil
+}
+
+// ParseStateDiagram returns a diagram defined in a text format
+// with one statement per line
+//
+//	# comments start with hash
+//	[*] -> Idle
+//	Idle -> Running : start [ready] / init()
+//	Running -> Running : tick
+//	state Running {
+//	  [*] -> Loading
+//	  Loading -> Serving : loaded
+//	}
+//	Running -> [*] : stop
+//
+// [*] is the initial state on the left side of the arrow and the
+// final state on the right side. States are created when first
+// mentioned in the current scope and reused by name within that
+// scope only, so states with the same name in different composite
+// states are kept apart.
+func ParseStateDiagram(r io.Reader) (*StateDiagram, error) {
+	d := NewStateDiagram()
+	scope := d.root
+	s := bufio.NewScanner(r)
+	var lineno int
+	for s.Scan() {
+		lineno++
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case line == "}":
+			if scope == d.root {
+				return nil, fmt.Errorf("line %v: unexpected }", lineno)
+			}
+			scope = scope.parent
+		case strings.HasPrefix(line, "state ") && strings.HasSuffix(line, "{"):
+			name := strings.TrimSpace(line[len("state ") : len(line)-1])
+			scope = d.lookup(scope, name)
+		case strings.Contains(line, "->"):
+			if err := d.parseTransition(scope, line); err != nil {
+				return nil, fmt.Errorf("line %v: %w", lineno, err)
+			}
+		default:
+			d.lookup(scope, line)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if scope != d.root {
+		return nil, fmt.Errorf("missing } for state %q", scope.Name)
+	}
+	return d, nil
+}
+
+// lookup returns the named sub state of scope or adds it.
+func (d *StateDiagram) lookup(scope *State, name string) *State {
+	for _, c := range scope.children {
+		if c.kind == simpleState && c.Name == name {
+			return c
+		}
+	}
+	return scope.State(name)
+}
+
+func (d *StateDiagram) parseTransition(scope *State, line string) error {
+	var label string
+	if i := strings.Index(line, ":"); i > -1 {
+		label = strings.TrimSpace(line[i+1:])
+		line = line[:i]
+	}
+	parts := strings.SplitN(line, "->", 2)
+	from := strings.TrimSpace(parts[0])
+	to := strings.TrimSpace(parts[1])
+	if from == "" || to == "" {
+		return fmt.Errorf("missing state in transition")
+	}
+	var t *Transition
+	switch {
+	case from == "[*]" && to == "[*]":
+		return fmt.Errorf("transition between pseudo states")
+	case from == "[*]":
+		t = d.Initial(d.lookup(scope, to))
+	case to == "[*]":
+		t = d.Final(d.lookup(scope, from), "")
+	default:
+		t = d.Transition(d.lookup(scope, from), d.lookup(scope, to), "")
+	}
+	t.Event, t.Guard, t.Action = parseTransitionLabel(label)
+	return nil
+}
+
+// parseTransitionLabel splits "event [guard] / action" into its
+// parts.
+func parseTransitionLabel(v string) (event, guard, action string) {
+	if i := strings.Index(v, "/"); i > -1 {
+		action = strings.TrimSpace(v[i+1:])
+		v = v[:i]
+	}
+	if i := strings.Index(v, "["); i > -1 {
+		if j := strings.LastIndex(v, "]"); j > i {
+			guard = strings.TrimSpace(v[i+1 : j])
+		}
+		v = v[:i]
+	}
+	event = strings.TrimSpace(v)
+	return
+}
+
+// SaveAs saves the diagram to filename as SVG
+func (d *StateDiagram) SaveAs(filename string) error {
+	return saveAs(d, d.Style, filename)
+}
+
+// Inline returns rendered SVG with inlined style
+func (d *StateDiagram) Inline() string {
+	return draw.Inline(d, d.Style)
+}
+
+// String returns rendered SVG
+func (d *StateDiagram) String() string { return toString(d) }
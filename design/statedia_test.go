@@ -0,0 +1,147 @@
+package design
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+)
+
+func TestStateDiagram(t *testing.T) {
+	var (
+		d       = NewStateDiagram()
+		idle    = d.State("Idle")
+		running = d.State("Running")
+		loading = running.State("Loading")
+		serving = running.State("Serving")
+	)
+	d.Initial(idle)
+	start := d.Transition(idle, running, "start")
+	start.Guard = "ready"
+	start.Action = "init()"
+	d.Transition(running, running, "tick")
+	d.Initial(loading)
+	d.Transition(loading, serving, "loaded")
+	d.Final(running, "stop")
+
+	assert := asserter.New(t)
+	assert().Equals(start.Label(), "start [ready] / init()")
+	assert(running.IsComposite()).Error("running is not composite")
+	assert(d.Find("Serving") == serving).Error("Find Serving failed")
+	assert(d.Find("nothing") == nil).Error("found missing state")
+
+	rows := d.rows(d.root)
+	assert().Equals(len(rows), 4)
+	assert(rows[1][0] == idle).Error("idle not second row")
+
+	got := d.String()
+	assert().Contains(got, `class="composite"`)
+	assert().Contains(got, "start [ready] / init()")
+
+	// sub states are inside the composite state
+	rx, ry := running.shape.Position()
+	sx, sy := serving.shape.Position()
+	assert(sx > rx && sy > ry).Error("serving outside running")
+	assert(sx+serving.shape.Width() < rx+running.shape.Width()).Error("serving too wide")
+}
+
+func TestStateDiagram_orphan(t *testing.T) {
+	d := NewStateDiagram()
+	orphan := d.State("Orphan")
+	a := d.State("A")
+	d.Initial(a)
+	d.WriteSVG(ioutil.Discard)
+
+	assert := asserter.New(t)
+	rows := d.rows(d.root)
+	assert().Equals(len(rows), 3)
+	assert(rows[2][0] == orphan).Error("orphan not in last row")
+	_, ay := a.shape.Position()
+	ox, oy := orphan.shape.Position()
+	assert(ox > 0 && oy > ay).Errorf("orphan at %v,%v", ox, oy)
+}
+
+func TestStateDiagram_Inline(t *testing.T) {
+	d, err := ParseStateDiagram(strings.NewReader(`
+[*] -> A
+A -> A : again
+A -> B : go [ok] / run()
+B -> [*]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := d.Inline()
+	if strings.Contains(got, "class") {
+		t.Error("found class attributes\n", got)
+	}
+}
+
+func TestParseStateDiagram(t *testing.T) {
+	d, err := ParseStateDiagram(strings.NewReader(`
+# comment
+[*] -> Idle
+Idle -> Running : start [ready] / init()
+state Running {
+  [*] -> Loading
+  Loading -> Serving : loaded
+}
+Running -> [*] : stop
+Lonely`))
+	assert := asserter.New(t)
+	assert(err == nil).Fatal(err)
+	running := d.Find("Running")
+	assert(running != nil).Fatal("missing Running")
+	assert(d.Find("Loading").parent == running).Error("Loading not in Running")
+	assert(d.Find("Lonely") != nil).Error("missing Lonely")
+	assert().Equals(len(d.transitions), 5)
+	tr := d.transitions[1]
+	assert().Equals(tr.Event, "start")
+	assert().Equals(tr.Guard, "ready")
+	assert().Equals(tr.Action, "init()")
+	assert().Equals(d.transitions[4].Label(), "stop")
+}
+
+func TestParseStateDiagram_scopes(t *testing.T) {
+	d, err := ParseStateDiagram(strings.NewReader(`
+[*] -> Idle
+state A {
+  [*] -> Idle
+  state Inner {
+    Idle -> Done
+  }
+}
+state B {
+  [*] -> Idle
+}`))
+	assert := asserter.New(t)
+	assert(err == nil).Fatal(err)
+	a, b := d.Find("A"), d.Find("B")
+	top := d.Find("Idle")
+	assert(top.parent == d.root).Error("top Idle not at top level")
+	assert(a.find("Idle") != top).Error("Idle in A reuses top level Idle")
+	assert(b.find("Idle") != a.find("Idle")).Error("Idle in B reuses Idle in A")
+	inner := d.Find("Inner")
+	assert(inner.find("Idle").parent == inner).Error("Idle in Inner not in Inner")
+
+	d.WriteSVG(ioutil.Discard)
+	ax, ay := a.shape.Position()
+	ix, iy := inner.shape.Position()
+	done := inner.find("Done")
+	dx, dy := done.shape.Position()
+	assert(ix > ax && iy > ay).Errorf("Inner %v,%v outside A %v,%v", ix, iy, ax, ay)
+	assert(dx > ix && dy > iy).Errorf("Done %v,%v outside Inner %v,%v", dx, dy, ix, iy)
+}
+
+func TestParseStateDiagram_errors(t *testing.T) {
+	bad := func(txt string) {
+		t.Helper()
+		if _, err := ParseStateDiagram(strings.NewReader(txt)); err == nil {
+			t.Errorf("expected error for %q", txt)
+		}
+	}
+	bad("}")
+	bad("state A {")
+	bad("[*] -> [*]")
+	bad("A -> ")
+}
@@ -0,0 +1,6 @@
+package broken
+
+func Run() {
+	var n int = "one"
+	missing(n)
+}
@@ -0,0 +1,40 @@
+-- Example web shop schema
+CREATE TABLE customer (
+    id          SERIAL PRIMARY KEY,
+    email       VARCHAR(120) NOT NULL UNIQUE,
+    name        TEXT
+);
+
+CREATE TABLE address (
+    customer_id INTEGER PRIMARY KEY REFERENCES customer (id),
+    street      TEXT NOT NULL,
+    city        TEXT NOT NULL
+);
+
+CREATE TABLE product (
+    id          SERIAL PRIMARY KEY,
+    name        TEXT NOT NULL,
+    price       NUMERIC(10, 2) NOT NULL,
+    replaced_by INTEGER REFERENCES product (id)
+);
+
+CREATE TABLE "order" (
+    id          SERIAL,
+    customer_id INTEGER NOT NULL,
+    created     TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    CONSTRAINT order_pk PRIMARY KEY (id),
+    CONSTRAINT order_customer_fk FOREIGN KEY (customer_id)
+        REFERENCES customer (id)
+);
+
+CREATE TABLE order_line (
+    order_id    INTEGER NOT NULL,
+    product_id  INTEGER NOT NULL,
+    quantity    INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (order_id, product_id)
+);
+
+ALTER TABLE order_line ADD FOREIGN KEY (order_id) REFERENCES "order" (id);
+ALTER TABLE order_line ADD CONSTRAINT line_product_fk
+    FOREIGN KEY (product_id) REFERENCES product (id);
+CREATE INDEX order_created ON "order" (created);
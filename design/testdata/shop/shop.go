@@ -0,0 +1,44 @@
+// Package shop is used for testing call sequences
+package shop
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Client struct {
+	srv *Server
+}
+
+func (c *Client) Buy(item string) error {
+	return c.srv.Order(strings.ToLower(item))
+}
+
+type Server struct {
+	db    Store
+	audit func(string)
+}
+
+func (s *Server) Order(item string) error {
+	s.validate(item)
+	s.audit(item)
+	if err := s.db.Save(item); err != nil {
+		return fmt.Errorf("order: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) validate(item string) {
+	if len(item) == 0 {
+		panic(fmt.Sprintf("empty item"))
+	}
+}
+
+type Store interface {
+	Save(string) error
+}
+
+func Run() {
+	c := &Client{srv: &Server{}}
+	c.Buy("Book")
+}
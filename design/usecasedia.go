@@ -0,0 +1,396 @@
+package design
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gregoryv/draw"
+	"github.com/gregoryv/draw/shape"
+	"github.com/gregoryv/draw/xy"
+)
+
+// NewUseCaseDiagram returns an empty use case diagram of the named
+// system.
+func NewUseCaseDiagram(system string) *UseCaseDiagram {
+	return &UseCaseDiagram{
+		Diagram:   NewDiagram(),
+		System:    system,
+		CaseWidth: 100,
+	}
+}
+
+// UseCaseDiagram shows the use cases of a system and the actors
+// taking part in them. Use cases are placed inside the system
+// boundary, included and extending use cases in a column right of
+// the others. Actors are placed outside the boundary next to their
+// use cases, primary actors on the left and secondary on the right.
+type UseCaseDiagram struct {
+	*Diagram
+
+	// System is the title of the boundary.
+	System string
+
+	// CaseWidth is the width use case text is wrapped to.
+	CaseWidth int
+
+	actors    []*UseCaseActor
+	cases     []*useCase
+	relations []*useCaseRelation
+}
+
+type useCase struct {
+	*shape.UseCase
+	text string
+}
+
+type relationKind int
+
+const (
+	association relationKind = iota
+	include
+	extend
+	generalization
+)
+
+type useCaseRelation struct {
+	kind     relationKind
+	from, to shape.Shape
+}
+
+// Actor adds a primary actor, placed left of the system.
+func (d *UseCaseDiagram) Actor(name string) *UseCaseActor {
+	a := NewUseCaseActor(name)
+	d.actors = append(d.actors, a)
+	return a
+}
+
+// SecondaryActor adds an actor the system relies on, placed right of
+// the system.
+func (d *UseCaseDiagram) SecondaryActor(name string) *UseCaseActor {
+	a := d.Actor(name)
+	a.Secondary = true
+	return a
+}
+
+// UseCase adds a use case described by txt, which is wrapped to
+// CaseWidth when the diagram is written.
+func (d *UseCaseDiagram) UseCase(txt string) *shape.UseCase {
+	uc := &useCase{
+		UseCase: shape.NewUseCase(txt),
+		text:    txt,
+	}
+	d.cases = append(d.cases, uc)
+	return uc.UseCase
+}
+
+// Associate links the actor with the use cases it takes part in.
+func (d *UseCaseDiagram) Associate(a *UseCaseActor, cases ...*shape.UseCase) {
+	for _, c := range cases {
+		d.relate(association, a, c)
+	}
+}
+
+// Include adds a dashed <<include>> arrow from base to the use case
+// it always includes.
+func (d *UseCaseDiagram) Include(base, included *shape.UseCase) {
+	d.relate(include, base, included)
+}
+
+// Extend adds a dashed <<extend>> arrow from extension to the use
+// case it optionally extends.
+func (d *UseCaseDiagram) Extend(extension, base *shape.UseCase) {
+	d.relate(extend, extension, base)
+}
+
+// Generalize adds an arrow from child to parent, e.g. between two
+// actors or two use cases.
+func (d *UseCaseDiagram) Generalize(child, parent shape.Shape) {
+	d.relate(generalization, child, parent)
+}
+
+func (d *UseCaseDiagram) relate(kind relationKind, from, to shape.Shape) {
+	d.relations = append(d.relations, &useCaseRelation{
+		kind: kind,
+		from: from,
+		to:   to,
+	})
+}
+
+// WriteSVG renders the diagram as SVG to the given writer.
+func (d *UseCaseDiagram) WriteSVG(w io.Writer) error {
+	for _, c := range d.cases {
+		c.Title = strings.Join(wrapText(d.Font, c.text, d.CaseWidth), "\n")
+		d.applyStyle(c.UseCase)
+	}
+	for _, a := range d.actors {
+		d.applyStyle(a)
+	}
+	// placed shapes are removed after writing so the diagram can be
+	// written again
+	content := d.Content
+	defer func() { d.Content = content }()
+	d.layout()
+	for _, r := range d.relations {
+		d.link(r)
+	}
+	return d.Diagram.WriteSVG(w)
+}
+
+// layout places the boundary, use cases and actors.
+func (d *UseCaseDiagram) layout() {
+	var (
+		left, right = d.actorColumns()
+		leftWidth   = widest(left)
+		x           = d.Pad.Left
+		y           = d.Pad.Top
+		gap         = 2 * d.Spacing
+	)
+	if len(left) > 0 {
+		x += leftWidth + gap
+	}
+	title := shape.NewLabel(d.System)
+	title.SetClass("system-title")
+	d.applyStyle(title)
+	top := d.TextPad.Top + title.Height() + d.TextPad.Bottom
+
+	// use cases in one or two columns inside the boundary
+	main, side := d.caseColumns()
+	cx := x + d.Spacing
+	bottom := d.stack(main, cx, y+top, nil)
+	if len(side) > 0 {
+		cx += widest(main) + 3*d.Spacing
+		if b := d.stack(side, cx, y+top, d.bases); b > bottom {
+			bottom = b
+		}
+		cx += widest(side)
+	} else {
+		cx += widest(main)
+	}
+	width := cx + d.Spacing - x
+	if w := title.Width() + 2*d.Spacing; w > width {
+		width = w
+	}
+	boundary := shape.NewRect("")
+	boundary.SetClass("system")
+	boundary.SetX(x)
+	boundary.SetY(y)
+	boundary.SetWidth(width)
+	boundary.SetHeight(bottom + d.Spacing - y)
+	d.Place(boundary)
+	d.Place(title).At(x+(width-title.Width())/2, y+d.TextPad.Top)
+	for _, c := range d.cases {
+		d.Place(c.UseCase)
+	}
+
+	// actors next to their use cases
+	d.stackActors(left, d.Pad.Left, leftWidth)
+	d.stackActors(right, x+width+gap, widest(right))
+}
+
+// actorColumns returns the primary and secondary actors.
+func (d *UseCaseDiagram) actorColumns() (left, right []shape.Shape) {
+	for _, a := range d.actors {
+		if a.Secondary {
+			right = append(right, a)
+			continue
+		}
+		left = append(left, a)
+	}
+	return
+}
+
+// caseColumns returns the use cases in the main column and those
+// included by, or extending, other use cases in the side column.
+func (d *UseCaseDiagram) caseColumns() (main, side []shape.Shape) {
+	for _, c := range d.cases {
+		if len(d.bases(c.UseCase)) > 0 {
+			side = append(side, c.UseCase)
+			continue
+		}
+		main = append(main, c.UseCase)
+	}
+	return
+}
+
+// stack places shapes centered in a column at x from top to bottom,
+// starting at y or as near the shapes returned by related as
+// possible. Returns the bottom of the column.
+func (d *UseCaseDiagram) stack(col []shape.Shape, x, y int, related func(shape.Shape) []shape.Shape) int {
+	w := widest(col)
+	for _, s := range col {
+		if related != nil {
+			if top := centeredOn(s, related(s)); top > y {
+				y = top
+			}
+		}
+		s.SetX(x + (w-s.Width())/2)
+		s.SetY(y)
+		y += s.Height() + d.Spacing
+	}
+	return y - d.Spacing
+}
+
+// stackActors places actors in a column at x next to the use cases
+// they are associated with, without overlapping each other.
+func (d *UseCaseDiagram) stackActors(col []shape.Shape, x, w int) {
+	y := d.Pad.Top
+	for _, a := range col {
+		if top := centeredOn(a, d.casesOf(a)); top > y {
+			y = top
+		}
+		d.Place(a).At(x+(w-a.Width())/2, y)
+		y += a.Height() + d.Spacing
+	}
+}
+
+// bases returns the use cases including, or extended by, c.
+func (d *UseCaseDiagram) bases(c shape.Shape) []shape.Shape {
+	bases := make([]shape.Shape, 0)
+	for _, r := range d.relations {
+		switch {
+		case r.kind == include && r.to == c:
+			bases = append(bases, r.from)
+		case r.kind == extend && r.from == c:
+			bases = append(bases, r.to)
+		}
+	}
+	return bases
+}
+
+// casesOf returns the use cases associated with the actor.
+func (d *UseCaseDiagram) casesOf(a shape.Shape) []shape.Shape {
+	cases := make([]shape.Shape, 0)
+	for _, r := range d.relations {
+		if r.kind == association && r.from == a {
+			cases = append(cases, r.to)
+		}
+	}
+	return cases
+}
+
+// centeredOn returns the y position of s centered on the middle of
+// the others, -1 if there are none.
+func centeredOn(s shape.Shape, others []shape.Shape) int {
+	if len(others) == 0 {
+		return -1
+	}
+	var sum int
+	for _, o := range others {
+		_, y := o.Position()
+		sum += y + o.Height()/2
+	}
+	return sum/len(others) - s.Height()/2
+}
+
+func widest(col []shape.Shape) int {
+	var w int
+	for _, s := range col {
+		if s.Width() > w {
+			w = s.Width()
+		}
+	}
+	return w
+}
+
+// link places the arrow of the relation.
+func (d *UseCaseDiagram) link(r *useCaseRelation) {
+	a := shape.NewArrowBetween(r.from, r.to)
+	switch r.kind {
+	case association:
+		a.Head = nil
+		a.SetClass("association")
+	case include:
+		a.SetClass("include-arrow")
+		a.AddLabel("«include»", shape.LabelMiddle)
+	case extend:
+		a.SetClass("extend-arrow")
+		a.AddLabel("«extend»", shape.LabelMiddle)
+	case generalization:
+		a.SetClass("generalize-arrow")
+	}
+	d.Place(a)
+}
+
+// SaveAs saves the diagram to filename as SVG
+func (d *UseCaseDiagram) SaveAs(filename string) error {
+	return saveAs(d, d.Style, filename)
+}
+
+// Inline returns rendered SVG with inlined style
+func (d *UseCaseDiagram) Inline() string {
+	return draw.Inline(d, d.Style)
+}
+
+// String returns rendered SVG
+func (d *UseCaseDiagram) String() string { return toString(d) }
+
+// NewUseCaseActor returns an actor with its name below.
+func NewUseCaseActor(name string) *UseCaseActor {
+	return &UseCaseActor{
+		Name:   name,
+		Font:   shape.DefaultFont,
+		figure: shape.NewActor(),
+	}
+}
+
+// UseCaseActor is a stick figure with a name.
+type UseCaseActor struct {
+	x, y int
+	Name string
+
+	// Secondary actors are placed right of the system.
+	Secondary bool
+
+	Font   shape.Font
+	figure *shape.Actor
+}
+
+func (a *UseCaseActor) String() string {
+	return fmt.Sprintf("Actor %q", a.Name)
+}
+
+func (a *UseCaseActor) Position() (int, int) { return a.x, a.y }
+func (a *UseCaseActor) SetX(x int)           { a.x = x }
+func (a *UseCaseActor) SetY(y int)           { a.y = y }
+func (a *UseCaseActor) Direction() shape.Direction {
+	return shape.DirectionRight
+}
+func (a *UseCaseActor) SetClass(c string)    { a.figure.SetClass(c) }
+func (a *UseCaseActor) SetFont(f shape.Font) { a.Font = f }
+
+func (a *UseCaseActor) Width() int {
+	if w := a.Font.TextWidth(a.Name); w > a.figure.Width() {
+		return w
+	}
+	return a.figure.Width()
+}
+
+func (a *UseCaseActor) Height() int {
+	return a.figure.Height() + a.Font.LineHeight
+}
+
+// Edge returns intersecting position of a line starting at start and
+// pointing to the actor center.
+func (a *UseCaseActor) Edge(start xy.Point) xy.Point {
+	box := shape.NewRect("")
+	box.SetX(a.x)
+	box.SetY(a.y)
+	box.SetWidth(a.Width())
+	box.SetHeight(a.Height())
+	return box.Edge(start)
+}
+
+func (a *UseCaseActor) WriteSVG(out io.Writer) error {
+	a.figure.SetX(a.x + (a.Width()-a.figure.Width())/2)
+	a.figure.SetY(a.y)
+	if err := a.figure.WriteSVG(out); err != nil {
+		return err
+	}
+	name := shape.NewLabel(a.Name)
+	name.Font = a.Font
+	name.SetClass("actor-title")
+	name.SetX(a.x + (a.Width()-name.Width())/2)
+	name.SetY(a.y + a.figure.Height())
+	return name.WriteSVG(out)
+}
@@ -0,0 +1,51 @@
+package design
+
+import (
+	"testing"
+
+	"github.com/gregoryv/asserter"
+)
+
+func TestUseCaseDiagram(t *testing.T) {
+	var (
+		d        = NewUseCaseDiagram("Shop")
+		customer = d.Actor("Customer")
+		member   = d.Actor("Member")
+		bank     = d.SecondaryActor("Bank")
+		buy      = d.UseCase("Buy products online")
+		pay      = d.UseCase("Pay")
+		coupon   = d.UseCase("Use coupon")
+	)
+	d.Associate(customer, buy)
+	d.Associate(bank, pay)
+	d.Include(buy, pay)
+	d.Extend(coupon, buy)
+	d.Generalize(member, customer)
+
+	assert := asserter.New(t)
+	main, side := d.caseColumns()
+	assert().Equals(len(main), 1)
+	assert().Equals(len(side), 2)
+
+	got := d.String()
+	assert().Contains(got, "«include»")
+	assert().Contains(got, "«extend»")
+	assert().Contains(got, `class="system"`)
+	assert().Contains(got, "Customer")
+
+	// actors are outside the boundary
+	cx, _ := customer.Position()
+	bx, _ := buy.Position()
+	assert(cx+customer.Width() < bx).Error("customer inside boundary")
+	kx, _ := bank.Position()
+	px, _ := pay.Position()
+	assert(kx > px+pay.Width()).Error("bank inside boundary")
+	// actors don't overlap
+	_, cy := customer.Position()
+	_, my := member.Position()
+	assert(my >= cy+customer.Height()).Error("member overlaps customer")
+	// text is wrapped
+	assert(buy.Width() < 2*d.CaseWidth).Errorf("buy not wrapped: %v", buy.Width())
+	// writing again renders the same
+	assert().Equals(d.String(), got)
+}
@@ -27,16 +27,24 @@ func Test_write_allshapes(t *testing.T) {
 	}
 
 	add("Arrow", NewArrow(240, 0, 300, 0))
+	add("Artifact", NewArtifact("app.jar"))
 	add("Circle", NewCircle(20))
 	add("Component", NewComponent("Component"))
 	lcomp := NewComponent("Component")
 	lcomp.SetHref("https://gregoryv.github.io/draw")
 	add("Component(linked)", lcomp)
+	crow := NewArrow(240, 0, 300, 0)
+	crow.Tail = NewCrowFoot(ZeroOrMany)
+	crow.Head = NewCrowFoot(ExactlyOne)
+	add("CrowFoot", crow)
 	add("Cylinder", NewCylinder(30, 40))
 	add("Database", NewDatabase("database"))
 	add("Diamond", NewDiamond())
 	add("Dot", NewDot())
 	add("ExitDot", NewExitDot())
+	group := NewGroup("group")
+	group.Add(NewRect("child"))
+	add("Group", group)
 
 	add("Hexagon", NewHexagon("Hexagon", 80, 40, 20))
 
@@ -44,7 +52,10 @@ func Test_write_allshapes(t *testing.T) {
 	add("Label", NewLabel("label-text"))
 
 	add("Line", NewLine(240, 0, 300, 0))
+	add("Lollipop", NewLollipop("Reader"))
+	add("Node", NewNode("server"))
 	add("Note", NewNote("This describes\nsomething..."))
+	add("Package", NewPackage("package"))
 
 	rec := NewRecord("record")
 	rec.Fields = []string{"fields"}
@@ -52,8 +63,10 @@ func Test_write_allshapes(t *testing.T) {
 	add("Record", rec)
 
 	add("Rect", NewRect("a rectangle"))
+	add("Socket", NewSocket("Writer"))
 	add("State", NewState("active"))
 	add("Triangle", NewTriangle())
+	add("UseCase", NewUseCase("Place\norder"))
 
 	d.SaveAs("allshapes.svg")
 }
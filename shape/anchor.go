@@ -0,0 +1,104 @@
+package shape
+
+import (
+	"math"
+
+	"github.com/gregoryv/draw/xy"
+)
+
+// Anchor is a position relative to the box of a shape. X and Y are
+// fractions of the width and height, ie. 0,0 is the top left corner
+// and 1,1 the bottom right.
+type Anchor struct {
+	X, Y float64
+}
+
+// Anchors on the sides and corners of a box.
+var (
+	North     = Anchor{X: 0.5, Y: 0}
+	NorthEast = Anchor{X: 1, Y: 0}
+	East      = Anchor{X: 1, Y: 0.5}
+	SouthEast = Anchor{X: 1, Y: 1}
+	South     = Anchor{X: 0.5, Y: 1}
+	SouthWest = Anchor{X: 0, Y: 1}
+	West      = Anchor{X: 0, Y: 0.5}
+	NorthWest = Anchor{X: 0, Y: 0}
+	Middle    = Anchor{X: 0.5, Y: 0.5}
+)
+
+// Point returns the absolute position of the anchor on the given
+// box.
+func (a Anchor) Point(b Box) xy.Point {
+	x, y := b.Position()
+	return xy.Point{
+		X: x + int(math.Round(a.X*float64(b.Width()))),
+		Y: y + int(math.Round(a.Y*float64(b.Height()))),
+	}
+}
+
+// Ports holds named anchors of a shape, e.g. "http" on the right
+// side of a component. The zero value has no ports.
+type Ports struct {
+	ports map[string]Anchor
+}
+
+// SetPort adds or replaces the named anchor.
+func (p *Ports) SetPort(name string, a Anchor) {
+	if p.ports == nil {
+		p.ports = make(map[string]Anchor)
+	}
+	p.ports[name] = a
+}
+
+// Port returns the named anchor, false if there is no such port.
+func (p *Ports) Port(name string) (Anchor, bool) {
+	a, found := p.ports[name]
+	return a, found
+}
+
+// HasPorts is implemented by shapes with named anchors.
+type HasPorts interface {
+	Port(name string) (Anchor, bool)
+}
+
+// attachment is an end of an arrow attached to a shape, at an anchor
+// or the edge facing the other end if anchor is nil.
+type attachment struct {
+	shape  Shape
+	anchor *Anchor
+}
+
+// point returns the anchor position, or the shape center if the
+// attachment is on the edge.
+func (t *attachment) point() xy.Point {
+	if t.anchor != nil {
+		return t.anchor.Point(t.shape)
+	}
+	x, y := t.shape.Position()
+	return xy.Point{
+		X: x + t.shape.Width()/2,
+		Y: y + t.shape.Height()/2,
+	}
+}
+
+// edge returns where a line from start enters the shape.
+func (t *attachment) edge(start xy.Point) xy.Point {
+	if t.anchor != nil {
+		return t.anchor.Point(t.shape)
+	}
+	if s, ok := t.shape.(Edge); ok {
+		return s.Edge(start)
+	}
+	return t.point()
+}
+
+// attach returns an attachment at the named port of s, or its edge
+// if s has no such port.
+func attach(s Shape, port string) *attachment {
+	if p, ok := s.(HasPorts); ok {
+		if a, found := p.Port(port); found {
+			return &attachment{shape: s, anchor: &a}
+		}
+	}
+	return &attachment{shape: s}
+}
@@ -0,0 +1,59 @@
+package shape
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/xy"
+)
+
+func TestAnchor_Point(t *testing.T) {
+	r := NewRect("")
+	r.SetX(10)
+	r.SetY(20)
+	r.SetWidth(100)
+	r.SetHeight(40)
+	assert := asserter.New(t)
+	assert().Equals(North.Point(r), xy.Point{X: 60, Y: 20})
+	assert().Equals(SouthEast.Point(r), xy.Point{X: 110, Y: 60})
+	assert().Equals(West.Point(r), xy.Point{X: 10, Y: 40})
+	assert().Equals(Anchor{X: 0.25, Y: 1}.Point(r), xy.Point{X: 35, Y: 60})
+}
+
+func TestNewAnchoredArrow(t *testing.T) {
+	a, b := NewRect("a"), NewRect("b")
+	b.SetX(100)
+	b.SetY(100)
+	arrow := NewAnchoredArrow(a, South, b, West)
+	assert := asserter.New(t)
+	assert().Equals(arrow.Start, South.Point(a))
+	assert().Equals(arrow.End, West.Point(b))
+
+	// stays attached when shapes move
+	Move(b, 30, 10)
+	x, y := arrow.Position()
+	assert().Equals(xy.Point{X: x, Y: y}, South.Point(a))
+	arrow.WriteSVG(ioutil.Discard)
+	assert().Equals(arrow.End, West.Point(b))
+}
+
+func TestNewPortArrow(t *testing.T) {
+	c := NewComponent("api")
+	c.SetPort("http", Anchor{X: 1, Y: 0.25})
+	c.SetPort("grpc", Anchor{X: 1, Y: 0.75})
+	db := NewDatabase("db")
+	db.SetX(200)
+	http := NewPortArrow(c, "http", db, "")
+	grpc := NewPortArrow(c, "grpc", db, "")
+
+	assert := asserter.New(t)
+	a, _ := c.Port("http")
+	assert().Equals(http.Start, a.Point(c))
+	assert(http.Start != grpc.Start).Error("ports at same position")
+	// ends without ports are on the edge
+	assert().Equals(http.End.X, 200)
+
+	_, found := c.Port("missing")
+	assert(!found).Error("found missing port")
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"math"
 
+	"github.com/gregoryv/draw"
 	"github.com/gregoryv/draw/xy"
 	"github.com/gregoryv/nexus"
 )
@@ -18,6 +19,7 @@ func NewArrow(x1, y1, x2, y2 int) *Arrow {
 		Start: xy.Point{x1, y1},
 		End:   xy.Point{x2, y2},
 		Head:  head,
+		Font:  DefaultFont,
 		class: "arrow",
 	}
 }
@@ -25,9 +27,74 @@ func NewArrow(x1, y1, x2, y2 int) *Arrow {
 type Arrow struct {
 	Start xy.Point
 	End   xy.Point
+	// Via are optional points between start and end, e.g. for
+	// arrows routed around other shapes.
+	Via   []xy.Point
 	Tail  Shape
 	Head  Shape
 	class string
+
+	// from and to are set if the arrow is attached to shapes
+	from, to *attachment
+	// shift is how far an attached arrow has been moved from its
+	// shapes
+	shift xy.Point
+
+	// Font of labels
+	Font   Font
+	labels []*arrowLabel
+	// placedAt is the start and end when labels were placed
+	placedAt [2]xy.Point
+
+	// Markers draws head and tail as SVG markers following the
+	// exact direction of the path, instead of rotated shapes.
+	Markers bool
+
+	Decoration
+}
+
+// NewAnchoredArrow returns an arrow from the anchor on one shape to
+// the anchor on another, e.g. from South of a to West of b. The
+// arrow stays attached if the shapes move before it is written.
+func NewAnchoredArrow(from Shape, fa Anchor, to Shape, ta Anchor) *Arrow {
+	return newAttachedArrow(
+		&attachment{shape: from, anchor: &fa},
+		&attachment{shape: to, anchor: &ta},
+	)
+}
+
+// NewPortArrow returns an arrow between named ports, see
+// Ports.SetPort. An end without such port is attached to the edge of
+// its shape. The arrow stays attached if the shapes move before it is
+// written.
+func NewPortArrow(from Shape, fromPort string, to Shape, toPort string) *Arrow {
+	return newAttachedArrow(attach(from, fromPort), attach(to, toPort))
+}
+
+func newAttachedArrow(from, to *attachment) *Arrow {
+	a := NewArrow(0, 0, 0, 0)
+	a.from, a.to = from, to
+	a.update()
+	return a
+}
+
+// update moves attached ends to the current position of their
+// shapes.
+func (a *Arrow) update() {
+	if a.from == nil || a.to == nil {
+		return
+	}
+	end := a.to.edge(a.from.point())
+	start := a.from.edge(end)
+	a.Start = xy.Point{X: start.X + a.shift.X, Y: start.Y + a.shift.Y}
+	a.End = xy.Point{X: end.X + a.shift.X, Y: end.Y + a.shift.Y}
+}
+
+// Detach stops the ends from following their shapes, e.g. before
+// routing the arrow by setting Start, End and Via.
+func (a *Arrow) Detach() {
+	a.update()
+	a.from, a.to = nil, nil
 }
 
 func (a *Arrow) String() string {
@@ -35,34 +102,98 @@ func (a *Arrow) String() string {
 }
 
 func (a *Arrow) WriteSVG(out io.Writer) error {
+	a.update()
 	w, err := nexus.NewPrinter(out)
 	x1, y1 := a.Start.XY()
 	x2, y2 := a.End.XY()
-	w.Printf(`<path class="%s" d="M%v,%v L%v,%v" />`, a.class, x1, y1, x2, y2)
+	w.Printf(`<path class="%s" d="M%v,%v`, a.class, x1, y1)
+	for _, p := range a.Via {
+		w.Printf(` L%v,%v`, p.X, p.Y)
+	}
+	w.Printf(` L%v,%v"`, x2, y2)
+	if len(a.Via) > 0 {
+		w.Print(` fill="none"`) // bent paths would otherwise be filled
+	}
+	w.Print(a.attrs())
+	if a.Markers {
+		head, tail := a.markers()
+		if tail != nil {
+			w.Printf(` marker-start="%s"`, draw.URL(tail))
+		}
+		if head != nil {
+			w.Printf(` marker-end="%s"`, draw.URL(head))
+		}
+	}
+	w.Print(" />")
 	w.Print("\n")
-	if a.Tail != nil {
-		w.Printf(`<g transform="rotate(%v %v %v)">`, a.angle(), x1, y1)
-		alignTail(a.Tail, x1, y1)
-		a.Tail.SetClass(a.class + "-tail")
-		a.Tail.WriteSVG(out)
+	if a.Tail != nil && !a.Markers {
+		w.Printf(`<g transform="rotate(%v %v %v)">`, a.first().angle(), x1, y1)
+		// a copy so the same shape can be used as head elsewhere
+		tail := cloneShape(a.Tail)
+		alignTail(tail, x1, y1)
+		tail.SetClass(a.class + "-tail")
+		tail.WriteSVG(out)
 		w.Print("</g>\n")
 	}
-	if a.Head != nil {
-		w.Printf(`<g transform="rotate(%v %v %v)">`, a.angle(), x2, y2)
-		a.Head.SetX(a.End.X)
-		a.Head.SetY(a.End.Y)
+	if a.Head != nil && !a.Markers {
+		w.Printf(`<g transform="rotate(%v %v %v)">`, a.last().angle(), x2, y2)
+		alignHead(a.Head, a.End.X, a.End.Y)
 		a.Head.SetClass(a.class + "-head")
 		a.Head.WriteSVG(out)
 		w.Print("</g>\n")
 	}
+	if len(a.labels) > 0 && a.placedAt != [2]xy.Point{a.Start, a.End} {
+		a.PlaceLabels()
+	}
+	for _, l := range a.labels {
+		l.WriteSVG(w)
+		w.Print("\n")
+	}
 	return *err
 }
 
+// first returns the first segment of the arrow.
+func (a *Arrow) first() *Arrow {
+	if len(a.Via) == 0 {
+		return a
+	}
+	return &Arrow{Start: a.Start, End: a.Via[0]}
+}
+
+// last returns the last segment of the arrow.
+func (a *Arrow) last() *Arrow {
+	if len(a.Via) == 0 {
+		return a
+	}
+	return &Arrow{Start: a.Via[len(a.Via)-1], End: a.End}
+}
+
+// alignHead places s so it ends at x,y.
+func alignHead(s Shape, x, y int) {
+	switch s := s.(type) {
+	case *Diamond:
+		s.SetX(x)
+		s.SetY(y - s.Height()/2)
+	default:
+		s.SetX(x)
+		s.SetY(y)
+	}
+}
+
+// alignTail places s so it starts at x,y, crow's feet are turned
+// to point away from x,y. Use it on copies of arrow tails.
 func alignTail(s Shape, x, y int) {
 	switch s := s.(type) {
 	case *Circle:
 		s.SetX(x)
 		s.SetY(y - s.Radius)
+	case *Diamond:
+		s.SetX(x)
+		s.SetY(y - s.Height()/2)
+	case *CrowFoot:
+		s.tail = true
+		s.SetX(x)
+		s.SetY(y)
 	default:
 		s.SetX(x)
 		s.SetY(y)
@@ -70,14 +201,20 @@ func alignTail(s Shape, x, y int) {
 }
 
 // AbsAngle
-func (a *Arrow) AbsAngle() int { return int(a.absAngle()) }
+func (a *Arrow) AbsAngle() int {
+	a.update()
+	return int(a.absAngle())
+}
 
 func (a *Arrow) absAngle() float64 {
 	return math.Abs(float64(a.angle()))
 }
 
 // Angle returns value in degrees. Right = 0, down = 90, left: 180, up = -90
-func (a *Arrow) Angle() int { return a.angle() }
+func (a *Arrow) Angle() int {
+	a.update()
+	return a.angle()
+}
 
 // angle returns degrees the head of an arrow should rotate depending
 // on direction
@@ -152,6 +289,7 @@ func (a *Arrow) DirQ4() bool {
 }
 
 func (a *Arrow) endpoints() (xy.Point, xy.Point) {
+	a.update()
 	return a.Start, a.End
 }
 
@@ -159,79 +297,93 @@ func radians2degrees(A float64) int {
 	return int(A * 180 / math.Pi)
 }
 
+// Height returns the height of the area covered by all points of
+// the arrow.
 func (a *Arrow) Height() int {
-	return intAbs(a.Start.Y - a.End.Y)
+	min, max := a.Bounds()
+	return max.Y - min.Y
 }
 
+// Width returns the width of the area covered by all points of the
+// arrow.
 func (a *Arrow) Width() int {
-	return intAbs(a.Start.X - a.End.X)
+	min, max := a.Bounds()
+	return max.X - min.X
 }
 
+// Position returns the start of the arrow.
 func (a *Arrow) Position() (int, int) {
+	a.update()
 	return a.Start.XY()
 }
 
-// CenterPosition
-func (a *Arrow) CenterPosition() (x int, y int) {
-	d := a.Direction()
-
-	if d.Is(DirectionRight) {
-		x = a.Start.X + a.Width()/2
-	} else {
-		x = a.Start.X - a.Width()/2
-	}
-
-	if d.Is(DirectionDown) {
-		y = a.Start.Y + a.Height()/2
-	} else {
-		y = a.Start.Y - a.Height()/2
+// Bounds returns the top left and bottom right corners of the area
+// covered by all points of the arrow.
+func (a *Arrow) Bounds() (min, max xy.Point) {
+	a.update()
+	min, max = a.Start, a.Start
+	for _, p := range append([]xy.Point{a.End}, a.Via...) {
+		if p.X < min.X {
+			min.X = p.X
+		}
+		if p.Y < min.Y {
+			min.Y = p.Y
+		}
+		if p.X > max.X {
+			max.X = p.X
+		}
+		if p.Y > max.Y {
+			max.Y = p.Y
+		}
 	}
-
 	return
 }
 
+// CenterPosition returns the center of the area covered by all
+// points of the arrow.
+func (a *Arrow) CenterPosition() (x int, y int) {
+	min, max := a.Bounds()
+	return min.X + (max.X-min.X)/2, min.Y + (max.Y-min.Y)/2
+}
+
+// SetX moves the arrow horizontally. Attached arrows keep the
+// distance to their shapes.
 func (a *Arrow) SetX(x int) {
+	a.update()
 	diff := a.Start.X - x
+	a.shift.X -= diff
 	a.Start.X = x
 	a.End.X = a.End.X - diff // Set X2 so the entire arrow moves
+	for i := range a.Via {
+		a.Via[i].X -= diff
+	}
 }
 
+// SetY moves the arrow vertically. Attached arrows keep the
+// distance to their shapes.
 func (a *Arrow) SetY(y int) {
+	a.update()
 	diff := a.Start.Y - y
+	a.shift.Y -= diff
 	a.Start.Y = y
 	a.End.Y = a.End.Y - diff // Set Y2 so the entire arrow moves
+	for i := range a.Via {
+		a.Via[i].Y -= diff
+	}
 }
 
 // Direction returns vertical or horizontal direction, Other if at an angle.
 // If Other, use arrow.DirQn() methods to check to which quadrant.
 func (a *Arrow) Direction() Direction {
+	a.update()
 	return NewDirection(a.Start, a.End)
 }
 
 func (a *Arrow) SetClass(c string) { a.class = c }
 
+// NewArrowBetween returns an arrow from the edge of a to the edge of
+// b, aiming center to center. The arrow stays attached if the shapes
+// move before it is written.
 func NewArrowBetween(a, b Shape) *Arrow {
-	ax, ay := a.Position()
-	bx, by := b.Position()
-
-	// From center to center
-	x1 := ax + a.Width()/2
-	y1 := ay + a.Height()/2
-	x2 := bx + b.Width()/2
-	y2 := by + b.Height()/2
-	arrow := NewArrow(x1, y1, x2, y2)
-	bs, ok := b.(Edge)
-	if ok {
-		p := bs.Edge(arrow.Start)
-		arrow.End.X = p.X
-		arrow.End.Y = p.Y
-	}
-	as, ok := a.(Edge)
-	if ok {
-		p := as.Edge(arrow.End)
-		arrow.Start.X = p.X
-		arrow.Start.Y = p.Y
-	}
-
-	return arrow
+	return newAttachedArrow(&attachment{shape: a}, &attachment{shape: b})
 }
@@ -3,11 +3,14 @@ package shape
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/gregoryv/asserter"
 	"github.com/gregoryv/draw"
+	"github.com/gregoryv/draw/xy"
 )
 
 func TestOneArrow(t *testing.T) {
@@ -24,6 +27,7 @@ func TestOneArrow(t *testing.T) {
 	// when
 	it.HasATail()
 	it.HasBothTailAndHead()
+	it.CanBend()
 
 	it.CanHaveASpecificClass()
 	it.CanMove()
@@ -134,6 +138,36 @@ func (t *OneArrow) saveAs(filename string) {
 	fh.Close()
 }
 
+func (t *OneArrow) CanBend() {
+	t.Start = xy.Point{X: 10, Y: 70}
+	t.End = xy.Point{X: 30, Y: 20}
+	t.Via = []xy.Point{{X: 60, Y: 70}, {X: 60, Y: 20}}
+	t.assert().Equals(t.Width(), 50)
+	t.assert().Equals(t.Height(), 50)
+	t.assert().Equals(t.last().Angle(), 180)
+	t.saveAs("testdata/arrow_with_bends.svg")
+	x, _ := t.Position()
+	t.SetX(x + 5)
+	t.assert().Equals(t.Via[0].X, 65)
+
+	// via points left of and above start
+	t.Start = xy.Point{X: 50, Y: 50}
+	t.End = xy.Point{X: 60, Y: 60}
+	t.Via = []xy.Point{{X: 10, Y: 50}, {X: 10, Y: 20}, {X: 60, Y: 20}}
+	x, y := t.Position()
+	t.assert().Equals(x, 50)
+	t.assert().Equals(y, 50)
+	t.assert().Equals(t.Width(), 50)
+	t.assert().Equals(t.Height(), 40)
+	cx, cy := t.CenterPosition()
+	t.assert().Equals(cx, 35)
+	t.assert().Equals(cy, 40)
+	t.SetY(30)
+	t.assert().Equals(t.Start.Y, 30)
+	t.assert().Equals(t.Via[1].Y, 0)
+	t.Via = nil
+}
+
 func (t *OneArrow) CanHaveASpecificClass() {
 	t.Helper()
 	t.class = "special"
@@ -185,6 +219,8 @@ func TestArrowBetweenShapes(t *testing.T) {
 	}
 
 	it.StartsAndEndsAtEdgeOfShapes()
+	it.FollowsMovedShapes()
+	it.KeepsDistanceWhenMoved()
 }
 
 type ArrowBetweenShapes struct {
@@ -210,6 +246,34 @@ func (t *ArrowBetweenShapes) StartsAndEndsAtEdgeOfShapes() {
 	writeSvgTo(t.T, "testdata/arrow_between_shapes.svg", svg)
 }
 
+func (t *ArrowBetweenShapes) FollowsMovedShapes() {
+	a, b := NewRect("A"), NewRect("B")
+	arrow := NewArrowBetween(a, b)
+	b.SetX(100)
+	arrow.WriteSVG(ioutil.Discard)
+	t.assert().Equals(arrow.Start, xy.Point{X: a.Width(), Y: a.Height() / 2})
+	t.assert().Equals(arrow.End, xy.Point{X: 100, Y: b.Height() / 2})
+}
+
+func (t *ArrowBetweenShapes) KeepsDistanceWhenMoved() {
+	a, b := NewRect("A"), NewRect("B")
+	b.SetX(100)
+	arrow := NewArrowBetween(a, b)
+	Move(arrow, 0, 5)
+	b.SetY(10)
+	x, y := arrow.Position()
+	same := NewArrowBetween(a, b)
+	sx, sy := same.Position()
+	t.assert().Equals(x, sx)
+	t.assert().Equals(y, sy+5)
+
+	arrow.Detach()
+	b.SetY(50)
+	x, y = arrow.Position()
+	t.assert().Equals(x, sx)
+	t.assert().Equals(y, sy+5)
+}
+
 func newSvg(width, height int, shapes ...draw.SVGWriter) *draw.SVG {
 	svg := &draw.SVG{}
 	svg.SetSize(width, height)
@@ -227,3 +291,51 @@ func writeSvgTo(t *testing.T, filename string, svg *draw.SVG) {
 	svg.WriteSVG(&style)
 	fh.Close()
 }
+
+func TestArrow_Markers(t *testing.T) {
+	a := NewArrow(0, 0, 100, 30)
+	a.Markers = true
+	b := NewArrow(0, 50, 100, 50)
+	b.Markers = true
+	b.Tail = NewCircle(3)
+	img := draw.NewSVG()
+	img.Append(a, b)
+	var buf bytes.Buffer
+	style := draw.NewStyle(&buf)
+	img.WriteSVG(&style)
+	got := buf.String()
+	assert := asserter.New(t)
+	// equal heads share one marker
+	assert().Equals(strings.Count(got, "<marker "), 2)
+	assert().Equals(strings.Count(got, "marker-end="), 2)
+	assert().Equals(strings.Count(got, "marker-start="), 1)
+	assert(!strings.Contains(got, "rotate(")).Error("heads drawn as shapes")
+	assert(!strings.Contains(got, `class="arrow-head"`)).Error("marker not styled")
+
+	// heads and tails are left as is
+	b.Tail.SetClass("dot")
+	b.Tail.SetX(7)
+	b.Defs()
+	x, _ := b.Tail.Position()
+	assert().Equals(x, 7)
+	buf.Reset()
+	b.Tail.WriteSVG(&buf)
+	assert().Contains(buf.String(), `class="dot"`)
+}
+
+func TestArrow_Tail(t *testing.T) {
+	foot := NewCrowFoot(ZeroOrMany)
+	a := NewArrow(10, 10, 50, 10)
+	a.Tail = foot
+	b := NewArrow(10, 30, 50, 30)
+	b.Head = foot
+	var tail, head bytes.Buffer
+	a.WriteSVG(&tail)
+	b.WriteSVG(&head)
+
+	assert := asserter.New(t)
+	// the tail is drawn from a copy, leaving the shared foot as is
+	assert(!foot.tail).Error("foot turned into a tail")
+	assert().Contains(tail.String(), `cx="26" cy="10"`)
+	assert().Contains(head.String(), `cx="34" cy="30"`)
+}
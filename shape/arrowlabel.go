@@ -0,0 +1,197 @@
+package shape
+
+import (
+	"math"
+
+	"github.com/gregoryv/draw/xy"
+)
+
+// LabelPosition is where along an arrow a label is placed.
+type LabelPosition int
+
+const (
+	// LabelStart is next to the start of the arrow, e.g. for a
+	// multiplicity.
+	LabelStart LabelPosition = iota
+	// LabelMiddle is halfway along the path.
+	LabelMiddle
+	// LabelEnd is next to the head of the arrow.
+	LabelEnd
+)
+
+type arrowLabel struct {
+	*Label
+	at LabelPosition
+
+	// placed is where the label was last placed, labels moved
+	// from there are left as is
+	placed xy.Point
+}
+
+// AddLabel adds a label at the given position along the arrow. The
+// label is placed beside the path when the arrow is written, see
+// PlaceLabels, unless moved by the caller.
+func (a *Arrow) AddLabel(txt string, at LabelPosition) *Label {
+	l := NewLabel(txt)
+	l.Font = a.Font
+	a.labels = append(a.labels, &arrowLabel{
+		Label:  l,
+		at:     at,
+		placed: xy.Point{X: l.x, Y: l.y},
+	})
+	return l
+}
+
+// Labels returns the labels added to the arrow.
+func (a *Arrow) Labels() []*Label {
+	labels := make([]*Label, len(a.labels))
+	for i, l := range a.labels {
+		labels[i] = l.Label
+	}
+	return labels
+}
+
+// SetFont sets the font of the arrow labels.
+func (a *Arrow) SetFont(f Font) {
+	a.Font = f
+	for _, l := range a.labels {
+		l.Font = f
+	}
+}
+
+// labelGap is the space between a label and the path of its arrow.
+const labelGap = 4
+
+// PlaceLabels places each label beside the arrow, above flat and
+// right of steep segments. A label is moved to the other side,
+// or further away, if it would overlap any of the obstacles or
+// previous labels. Obstacles containing the label anchor, e.g. a
+// group surrounding the arrow, are ignored. Labels positioned by
+// the caller, ie. moved since last placed, are left as is.
+func (a *Arrow) PlaceLabels(obstacles ...Shape) {
+	a.update()
+	others := append([]Shape{}, obstacles...)
+	for _, l := range a.labels {
+		x, y := l.Position()
+		if (xy.Point{X: x, Y: y}) == l.placed {
+			a.placeLabel(l, others)
+			x, y = l.Position()
+			l.placed = xy.Point{X: x, Y: y}
+		}
+		others = append(others, l.Label)
+	}
+	a.placedAt = [2]xy.Point{a.Start, a.End}
+}
+
+// placeLabel places the label on the first free spot beside the
+// path.
+func (a *Arrow) placeLabel(l *arrowLabel, obstacles []Shape) {
+	p, dx, dy := a.labelAnchor(l)
+	// normal pointing up, or right if the segment is steep
+	nx, ny := dy, -dx
+	steep := math.Abs(dy) > math.Abs(dx)
+	if (steep && nx < 0) || (!steep && ny > 0) {
+		nx, ny = -nx, -ny
+	}
+	w, h := float64(l.Width()), float64(l.Height())
+	extent := math.Abs(nx)*w/2 + math.Abs(ny)*h/2
+	relevant := make([]Shape, 0, len(obstacles))
+	for _, o := range obstacles {
+		if !contains(o, p) {
+			relevant = append(relevant, o)
+		}
+	}
+	moveTo := func(side, dist float64) {
+		cx := float64(p.X) + side*nx*(dist+extent)
+		cy := float64(p.Y) + side*ny*(dist+extent)
+		l.SetX(int(math.Round(cx - w/2)))
+		l.SetY(int(math.Round(cy - h/2)))
+	}
+	for step := 0; step < 4; step++ {
+		dist := float64(labelGap + step*l.Font.LineHeight)
+		for _, side := range []float64{1, -1} {
+			moveTo(side, dist)
+			if !overlapsAny(l.Label, relevant) {
+				return
+			}
+		}
+	}
+	moveTo(1, labelGap)
+}
+
+// labelAnchor returns the point on the path where the label is
+// placed and the unit direction of the path at that point.
+func (a *Arrow) labelAnchor(l *arrowLabel) (xy.Point, float64, float64) {
+	points := a.points()
+	switch l.at {
+	case LabelStart:
+		dx, dy := unit(points[0], points[1])
+		along := labelGap + math.Abs(dx)*float64(l.Width())/2 + math.Abs(dy)*float64(l.Height())/2
+		return pointAlong(points[0], dx, dy, along), dx, dy
+	case LabelEnd:
+		n := len(points)
+		dx, dy := unit(points[n-2], points[n-1])
+		along := labelGap + math.Abs(dx)*float64(l.Width())/2 + math.Abs(dy)*float64(l.Height())/2
+		return pointAlong(points[n-1], dx, dy, -along), dx, dy
+	}
+	var length float64
+	for i := 1; i < len(points); i++ {
+		length += points[i-1].Distance(points[i])
+	}
+	half := length / 2
+	for i := 1; i < len(points); i++ {
+		seg := points[i-1].Distance(points[i])
+		if seg >= half || i == len(points)-1 {
+			dx, dy := unit(points[i-1], points[i])
+			return pointAlong(points[i-1], dx, dy, half), dx, dy
+		}
+		half -= seg
+	}
+	return points[0], 1, 0
+}
+
+// points returns start, via and end points of the arrow.
+func (a *Arrow) points() []xy.Point {
+	points := append([]xy.Point{a.Start}, a.Via...)
+	return append(points, a.End)
+}
+
+// unit returns the unit direction from p to q, right if they are the
+// same.
+func unit(p, q xy.Point) (float64, float64) {
+	d := p.Distance(q)
+	if d == 0 {
+		return 1, 0
+	}
+	return float64(q.X-p.X) / d, float64(q.Y-p.Y) / d
+}
+
+func pointAlong(p xy.Point, dx, dy, dist float64) xy.Point {
+	return xy.Point{
+		X: p.X + int(math.Round(dx*dist)),
+		Y: p.Y + int(math.Round(dy*dist)),
+	}
+}
+
+// contains returns true if p is inside the box of s.
+func contains(s Shape, p xy.Point) bool {
+	x, y := s.Position()
+	return p.X > x && p.X < x+s.Width() && p.Y > y && p.Y < y+s.Height()
+}
+
+func overlapsAny(s Shape, others []Shape) bool {
+	for _, o := range others {
+		if overlaps(s, o) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps returns true if the boxes of a and b intersect.
+func overlaps(a, b Shape) bool {
+	ax, ay := a.Position()
+	bx, by := b.Position()
+	return ax < bx+b.Width() && bx < ax+a.Width() &&
+		ay < by+b.Height() && by < ay+a.Height()
+}
@@ -0,0 +1,91 @@
+package shape
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+)
+
+func TestArrow_AddLabel(t *testing.T) {
+	a := NewArrow(0, 100, 200, 100)
+	start := a.AddLabel("1", LabelStart)
+	middle := a.AddLabel("has", LabelMiddle)
+	end := a.AddLabel("0..*", LabelEnd)
+	a.PlaceLabels()
+
+	assert := asserter.New(t)
+	for _, l := range a.Labels() {
+		_, y := l.Position()
+		assert(y+l.Height() <= 100).Errorf("%v not above arrow", l)
+	}
+	sx, _ := start.Position()
+	assert(sx > 0 && sx < 20).Errorf("start label at %v", sx)
+	mx, _ := middle.Position()
+	assert().Equals(mx+middle.Width()/2, 100)
+	ex, _ := end.Position()
+	assert(ex+end.Width() < 200 && ex > 150).Errorf("end label at %v", ex)
+}
+
+func TestArrow_PlaceLabels_vertical(t *testing.T) {
+	a := NewArrow(50, 0, 50, 100)
+	l := a.AddLabel("down", LabelMiddle)
+	a.PlaceLabels()
+	x, y := l.Position()
+	assert := asserter.New(t)
+	assert(x > 50).Errorf("label not right of arrow: %v", x)
+	assert().Equals(y+l.Height()/2, 50)
+}
+
+func TestArrow_PlaceLabels_avoids(t *testing.T) {
+	a := NewArrow(0, 100, 200, 100)
+	l := a.AddLabel("label", LabelMiddle)
+	// something right above the arrow
+	r := NewRect("block")
+	r.SetX(80)
+	r.SetY(60)
+	a.PlaceLabels(r)
+	_, y := l.Position()
+	assert := asserter.New(t)
+	assert(y > 100).Errorf("label not moved below arrow: %v", y)
+
+	// labels of the same arrow don't overlap
+	b := NewArrow(0, 100, 200, 100)
+	first := b.AddLabel("first", LabelMiddle)
+	second := b.AddLabel("second", LabelMiddle)
+	b.PlaceLabels()
+	assert(!overlaps(first, second)).Error("labels overlap")
+}
+
+func TestArrow_labels_follow_shapes(t *testing.T) {
+	from, to := NewRect("a"), NewRect("b")
+	to.SetX(200)
+	a := NewArrowBetween(from, to)
+	l := a.AddLabel("uses", LabelMiddle)
+	a.WriteSVG(ioutil.Discard)
+	x1, _ := l.Position()
+	to.SetX(300)
+	a.WriteSVG(ioutil.Discard)
+	x2, _ := l.Position()
+	assert := asserter.New(t)
+	assert(x2 > x1).Error("label did not follow")
+}
+
+func TestArrow_PlaceLabels_keeps_explicit(t *testing.T) {
+	a := NewArrow(0, 100, 200, 100)
+	before := a.AddLabel("before", LabelStart)
+	before.SetX(10)
+	before.SetY(150)
+	after := a.AddLabel("after", LabelEnd)
+	a.PlaceLabels()
+	after.SetX(20)
+	after.SetY(160)
+	a.End.Y = 300
+	a.PlaceLabels()
+
+	assert := asserter.New(t)
+	x, y := before.Position()
+	assert(x == 10 && y == 150).Errorf("before moved to %v,%v", x, y)
+	x, y = after.Position()
+	assert(x == 20 && y == 160).Errorf("after moved to %v,%v", x, y)
+}
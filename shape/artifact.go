@@ -0,0 +1,83 @@
+package shape
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gregoryv/draw/xy"
+	"github.com/gregoryv/nexus"
+)
+
+// NewArtifact returns a document with a folded corner, e.g. a file
+// or UML artifact.
+func NewArtifact(title string) *Artifact {
+	return &Artifact{
+		Title: title,
+		Font:  DefaultFont,
+		Pad:   DefaultTextPad,
+		class: "artifact",
+	}
+}
+
+type Artifact struct {
+	x, y  int
+	Title string
+
+	Font  Font
+	Pad   Padding
+	class string
+
+	Ports
+	Decoration
+}
+
+func (a *Artifact) String() string {
+	return fmt.Sprintf("Artifact %q", a.Title)
+}
+
+func (a *Artifact) Position() (int, int) { return a.x, a.y }
+func (a *Artifact) SetX(x int)           { a.x = x }
+func (a *Artifact) SetY(y int)           { a.y = y }
+func (a *Artifact) Direction() Direction { return DirectionRight }
+func (a *Artifact) SetClass(c string)    { a.class = c }
+
+func (a *Artifact) SetFont(f Font)         { a.Font = f }
+func (a *Artifact) SetTextPad(pad Padding) { a.Pad = pad }
+
+func (a *Artifact) WriteSVG(out io.Writer) error {
+	w, err := nexus.NewPrinter(out)
+	width, height := a.Width(), a.Height()
+	fold := a.fold()
+	w.Printf(`<path class="%s" d="M%v,%v h%v l%v,%v v%v h%v Z"%s/>`,
+		a.class, a.x, a.y, width-fold, fold, fold, height-fold, -width, a.attrs())
+	w.Printf("\n")
+	w.Printf(`<path class="%s" d="M%v,%v v%v h%v"/>`,
+		a.class, a.x+width-fold, a.y, fold, fold)
+	w.Printf("\n")
+	title := &Label{
+		x:     a.x + a.Pad.Left,
+		y:     a.y + a.Pad.Top/2 + fold/2,
+		Font:  a.Font,
+		Text:  a.Title,
+		class: a.class + "-title",
+	}
+	title.WriteSVG(w)
+	return *err
+}
+
+// fold returns the size of the folded corner.
+func (a *Artifact) fold() int { return a.Font.LineHeight / 2 }
+
+func (a *Artifact) Width() int {
+	return boxWidth(a.Font, a.Pad, a.Title) + a.fold()
+}
+
+func (a *Artifact) Height() int {
+	return boxHeight(a.Font, a.Pad, 1) + a.fold()
+}
+
+// Edge returns intersecting position of a line starting at start and
+// pointing to the artifact center.
+func (a *Artifact) Edge(start xy.Point) xy.Point {
+	return boxEdge(start, a)
+}
@@ -19,6 +19,8 @@ type Circle struct {
 	x, y   int // top left
 	Radius int
 	class  string
+
+	Decoration
 }
 
 func (c *Circle) String() string {
@@ -43,8 +45,8 @@ func (c *Circle) WriteSVG(out io.Writer) error {
 	x += c.Radius
 	y += c.Radius
 	w.Printf(
-		`<circle class="%s" cx="%v" cy="%v" r="%v" />\n`,
-		c.class, x, y, c.Radius,
+		`<circle class="%s" cx="%v" cy="%v" r="%v"%s />\n`,
+		c.class, x, y, c.Radius, c.attrs(),
 	)
 	return *err
 }
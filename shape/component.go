@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/gregoryv/draw"
 	"github.com/gregoryv/draw/xy"
 	"github.com/gregoryv/nexus"
 )
@@ -31,6 +32,12 @@ type Component struct {
 	//smallBoxWidth
 	sbWidth  int
 	sbHeight int
+
+	// Icon is drawn left of the title, e.g. a logo.
+	Icon *Image
+
+	Ports
+	Decoration
 }
 
 func (c *Component) String() string {
@@ -44,11 +51,15 @@ func (c *Component) Direction() Direction { return DirectionRight }
 func (c *Component) SetClass(v string)    { c.class = v }
 func (c *Component) SetHref(v string)     { c.href = v }
 
+// Defs returns the definitions referred to by the decoration of the
+// component and its icon.
+func (c *Component) Defs() []draw.Def { return iconDefs(&c.Decoration, c.Icon) }
+
 func (c *Component) WriteSVG(out io.Writer) error {
 	w, err := nexus.NewPrinter(out)
 	w.Printf(
-		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"/>`,
-		c.class, c.X, c.Y, c.Width(), c.Height())
+		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"%s/>`,
+		c.class, c.X, c.Y, c.Width(), c.Height(), c.rectAttrs())
 	w.Printf("\n")
 	// small boxes
 	w.Printf(
@@ -57,15 +68,21 @@ func (c *Component) WriteSVG(out io.Writer) error {
 	w.Printf(
 		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"/>`,
 		c.class, c.X-c.sbWidth/2, c.Y+c.Height()-c.sbHeight*2, c.sbWidth, c.sbHeight)
+	if c.Icon != nil {
+		w.Printf("\n")
+		writeIcon(w, c.Icon, c.X+c.Pad.Left+c.sbWidth/2, c.Y, c.Height())
+		w.Printf("\n")
+	}
 
 	c.title().WriteSVG(w)
 	return *err
 }
 
 func (c *Component) title() *Label {
+	dw, dh := iconSize(c.Icon, c.Font, c.Pad)
 	return &Label{
-		x:     c.X + c.Pad.Left + c.sbWidth/2,
-		y:     c.Y + c.Pad.Top/2,
+		x:     c.X + c.Pad.Left + c.sbWidth/2 + dw,
+		y:     c.Y + c.Pad.Top/2 + dh/2,
 		Font:  c.Font,
 		Text:  c.Title,
 		href:  c.href,
@@ -77,11 +94,13 @@ func (c *Component) SetFont(f Font)         { c.Font = f }
 func (c *Component) SetTextPad(pad Padding) { c.Pad = pad }
 
 func (c *Component) Height() int {
-	return boxHeight(c.Font, c.Pad, 1)
+	_, dh := iconSize(c.Icon, c.Font, c.Pad)
+	return boxHeight(c.Font, c.Pad, 1) + dh
 }
 
 func (c *Component) Width() int {
-	return boxWidth(c.Font, c.Pad, c.Title) + c.sbWidth/2
+	dw, _ := iconSize(c.Icon, c.Font, c.Pad)
+	return boxWidth(c.Font, c.Pad, c.Title) + c.sbWidth/2 + dw
 }
 
 // Edge returns intersecting position of a line starting at start and
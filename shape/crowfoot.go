@@ -0,0 +1,99 @@
+package shape
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gregoryv/nexus"
+)
+
+// Cardinality of one end in an entity relationship.
+type Cardinality int
+
+const (
+	ExactlyOne Cardinality = iota
+	ZeroOrOne
+	OneOrMany
+	ZeroOrMany
+)
+
+func (c Cardinality) String() string {
+	switch c {
+	case ExactlyOne:
+		return "1"
+	case ZeroOrOne:
+		return "0..1"
+	case OneOrMany:
+		return "1..*"
+	default:
+		return "0..*"
+	}
+}
+
+// NewCrowFoot returns an arrow end in crow's foot notation for the
+// given cardinality. Use it as head or tail of an arrow.
+func NewCrowFoot(c Cardinality) *CrowFoot {
+	return &CrowFoot{
+		Cardinality: c,
+		class:       "crowfoot",
+	}
+}
+
+type CrowFoot struct {
+	x, y int
+	Cardinality
+	class string
+
+	tail bool // drawn pointing away from the end of the line
+}
+
+func (c *CrowFoot) String() string {
+	return fmt.Sprintf("crowfoot %v at %v,%v", c.Cardinality, c.x, c.y)
+}
+
+func (c *CrowFoot) Position() (int, int) { return c.x, c.y }
+func (c *CrowFoot) SetX(x int)           { c.x = x }
+func (c *CrowFoot) SetY(y int)           { c.y = y }
+func (c *CrowFoot) Width() int           { return 20 }
+func (c *CrowFoot) Height() int          { return 12 }
+func (c *CrowFoot) Direction() Direction { return DirectionRight }
+func (c *CrowFoot) SetClass(v string)    { c.class = v }
+
+func (c *CrowFoot) WriteSVG(out io.Writer) error {
+	w, err := nexus.NewPrinter(out)
+	// drawn as if the line points straight to the right ending at
+	// x,y, ie. the entity is to the right
+	dir := -1
+	if c.tail {
+		dir = 1
+	}
+	x, y := c.x, c.y
+	bar := func(dx int) {
+		w.Printf(" M%v,%v l0,12", x+dir*dx, y-6)
+	}
+	w.Printf(`<path class="%s" d="`, c.class)
+	many := c.Cardinality == OneOrMany || c.Cardinality == ZeroOrMany
+	if many {
+		w.Printf("M%v,%v L%v,%v M%v,%v L%v,%v",
+			x+dir*10, y, x, y-6, x+dir*10, y, x, y+6)
+	}
+	var circle int
+	switch c.Cardinality {
+	case ExactlyOne:
+		bar(4)
+		bar(8)
+	case ZeroOrOne:
+		bar(4)
+		circle = 12
+	case OneOrMany:
+		bar(14)
+	case ZeroOrMany:
+		circle = 16
+	}
+	w.Print(`" />`)
+	if circle > 0 {
+		w.Printf(`<circle class="%s" cx="%v" cy="%v" r="4" />`,
+			c.class, x+dir*circle, y)
+	}
+	return *err
+}
@@ -27,6 +27,9 @@ type Cylinder struct {
 	x, y   int // top left
 	height int
 	class  string
+
+	Ports
+	Decoration
 }
 
 func (c *Cylinder) String() string {
@@ -64,10 +67,10 @@ func (c *Cylinder) WriteSVG(out io.Writer) error {
 		x, y+h+ry*2, x+rx*2, y+h+ry*2, x+rx*2, y+h,
 	)
 	w.Printf("L %v %v", x+rx*2, y+ry)
-	w.Print("\" />\n")
+	w.Printf("\"%s />\n", c.attrs())
 	w.Printf(
-		"<ellipse class=\"%s\" cx=\"%v\" cy=\"%v\" rx=\"%v\" ry=\"%v\" />\n",
-		c.class, cx, cy, rx, ry,
+		"<ellipse class=\"%s\" cx=\"%v\" cy=\"%v\" rx=\"%v\" ry=\"%v\"%s />\n",
+		c.class, cx, cy, rx, ry, c.attrs(),
 	)
 	return *err
 }
@@ -0,0 +1,84 @@
+package shape
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gregoryv/draw"
+)
+
+// Decoration holds optional presentation attributes written on the
+// outline of a shape in addition to its class, e.g. to make one
+// rectangle rounded and dashed. The zero value adds nothing.
+type Decoration struct {
+	// CornerRadius rounds the corners of rectangles.
+	CornerRadius int
+
+	// Dash is the stroke-dasharray, e.g. "5,3".
+	Dash string
+
+	// Opacity from 0 to 1, zero means opaque.
+	Opacity float64
+
+	// Shadow adds a drop shadow, see DropShadow.
+	Shadow bool
+
+	// Fill overrides the fill of the class with a definition, e.g. a
+	// draw.LinearGradient or draw.Hatch.
+	Fill draw.Def
+}
+
+// Decorate replaces the decoration.
+func (d *Decoration) Decorate(v Decoration) { *d = v }
+
+// Defs returns the definitions referred to by the decoration.
+func (d *Decoration) Defs() []draw.Def {
+	defs := make([]draw.Def, 0)
+	if d.Shadow {
+		defs = append(defs, DropShadow)
+	}
+	if d.Fill != nil {
+		defs = append(defs, d.Fill)
+	}
+	return defs
+}
+
+// DropShadow is the filter used by decorations with a shadow.
+var DropShadow = &draw.Filter{
+	ID:         "shadow",
+	Primitives: `<feDropShadow dx="2" dy="2" stdDeviation="2" flood-opacity="0.3"/>`,
+}
+
+// attrs returns the decoration as attributes, each with a leading
+// space. Corner radius is left out, see rectAttrs.
+func (d *Decoration) attrs() string {
+	var s strings.Builder
+	if d.Dash != "" {
+		fmt.Fprintf(&s, ` stroke-dasharray="%s"`, d.Dash)
+	}
+	if d.Opacity > 0 {
+		fmt.Fprintf(&s, ` opacity="%v"`, d.Opacity)
+	}
+	if d.Shadow {
+		fmt.Fprintf(&s, ` filter="%s"`, draw.URL(DropShadow))
+	}
+	if d.Fill != nil {
+		// style takes precedence over the fill of the class
+		fmt.Fprintf(&s, ` style="fill:%s"`, draw.URL(d.Fill))
+	}
+	return s.String()
+}
+
+// rectAttrs returns attributes of rect elements, including rounded
+// corners.
+func (d *Decoration) rectAttrs() string {
+	if d.CornerRadius == 0 {
+		return d.attrs()
+	}
+	return fmt.Sprintf(` rx="%v" ry="%v"`, d.CornerRadius, d.CornerRadius) + d.attrs()
+}
+
+// Decorated is implemented by shapes with a decoration.
+type Decorated interface {
+	Decorate(Decoration)
+}
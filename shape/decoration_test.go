@@ -0,0 +1,66 @@
+package shape
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw"
+)
+
+func TestDecoration(t *testing.T) {
+	r := NewRect("a")
+	r.Decorate(Decoration{
+		CornerRadius: 5,
+		Dash:         "5,3",
+		Opacity:      0.5,
+		Shadow:       true,
+	})
+	var buf bytes.Buffer
+	r.WriteSVG(&buf)
+	got := buf.String()
+	assert := asserter.New(t)
+	assert().Contains(got, `rx="5" ry="5"`)
+	assert().Contains(got, `stroke-dasharray="5,3"`)
+	assert().Contains(got, `opacity="0.5"`)
+	assert().Contains(got, `filter="url(#shadow)"`)
+
+	// only rectangles are rounded
+	c := NewCircle(10)
+	c.CornerRadius = 5
+	buf.Reset()
+	c.WriteSVG(&buf)
+	assert(!strings.Contains(buf.String(), "rx=")).Error(buf.String())
+}
+
+func TestDecoration_Defs(t *testing.T) {
+	a, b := NewRect("a"), NewUseCase("b")
+	a.Shadow = true
+	b.Shadow = true
+	g := NewGroup("g")
+	g.Add(b)
+
+	img := draw.NewSVG()
+	img.Append(a, g, NewRect("plain"))
+	var buf bytes.Buffer
+	style := draw.NewStyle(&buf)
+	img.WriteSVG(&style)
+	got := buf.String()
+	assert := asserter.New(t)
+	assert().Equals(strings.Count(got, `<filter id="shadow"`), 1)
+	assert().Equals(strings.Count(got, `filter="url(#shadow)"`), 2)
+	assert(strings.Index(got, "<defs>") < strings.Index(got, "<rect")).Error(
+		"defs must come before content",
+	)
+}
+
+func TestDecoration_Fill(t *testing.T) {
+	r := NewRect("a")
+	r.Fill = draw.NewHatch("out", "#d3d3d3")
+	var buf bytes.Buffer
+	r.WriteSVG(&buf)
+	assert := asserter.New(t)
+	assert().Contains(buf.String(), `style="fill:url(#out)"`)
+	assert().Equals(len(r.Defs()), 1)
+}
@@ -37,9 +37,11 @@ func (d *Diamond) String() string {
 
 func (d *Diamond) Position() (int, int) { return d.x, d.y }
 func (d *Diamond) SetX(x int)           { d.x = x }
-func (d *Diamond) SetY(y int)           { d.y = y - d.height/2 }
+func (d *Diamond) SetY(y int)           { d.y = y }
 func (d *Diamond) Width() int           { return d.width }
 func (d *Diamond) Height() int          { return d.height }
+func (d *Diamond) SetWidth(w int)       { d.width = w }
+func (d *Diamond) SetHeight(h int)      { d.height = h }
 func (d *Diamond) Direction() Direction { return DirectionRight }
 func (d *Diamond) SetClass(c string)    { d.class = c }
 
@@ -0,0 +1,214 @@
+package shape
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gregoryv/draw"
+	"github.com/gregoryv/draw/xy"
+	"github.com/gregoryv/nexus"
+)
+
+// NewGroup returns an empty group with the given title.
+func NewGroup(title string) *Group {
+	return &Group{
+		Title: title,
+		Font:  DefaultFont,
+		Pad:   DefaultPad,
+		class: "group",
+	}
+}
+
+// Group holds child shapes and is sized to fit them. Children are
+// laid out in a row, or column if Vertical, below the title and
+// move with the group.
+type Group struct {
+	x, y  int
+	Title string
+
+	// Vertical lays out children from top to bottom, default is
+	// left to right.
+	Vertical bool
+
+	// Spacing between children, DefaultSpacing if zero. Nested
+	// groups without spacing use the spacing of their parent.
+	Spacing int
+
+	Font  Font
+	Pad   Padding
+	class string
+
+	children []Shape
+
+	Ports
+	Decoration
+}
+
+func (g *Group) String() string { return fmt.Sprintf("G %q", g.Title) }
+
+// Add adds shapes to the group and places them.
+func (g *Group) Add(s ...Shape) {
+	g.children = append(g.children, s...)
+	g.layout(g.x, g.y)
+}
+
+// Children returns the shapes in the group.
+func (g *Group) Children() []Shape { return g.children }
+
+func (g *Group) Position() (int, int) { return g.x, g.y }
+
+// SetX moves the group and its children.
+func (g *Group) SetX(x int) { g.layout(x, g.y) }
+
+// SetY moves the group and its children.
+func (g *Group) SetY(y int) { g.layout(g.x, y) }
+
+func (g *Group) Direction() Direction { return DirectionRight }
+func (g *Group) SetClass(c string)    { g.class = c }
+
+// SetFont sets the font of the group and all children with text.
+func (g *Group) SetFont(f Font) {
+	g.Font = f
+	for _, c := range g.children {
+		if c, ok := c.(HasFont); ok {
+			c.SetFont(f)
+		}
+	}
+	g.layout(g.x, g.y)
+}
+
+// SetTextPad sets the text padding of all children with text.
+func (g *Group) SetTextPad(pad Padding) {
+	for _, c := range g.children {
+		if c, ok := c.(HasTextPad); ok {
+			c.SetTextPad(pad)
+		}
+	}
+	g.layout(g.x, g.y)
+}
+
+func (g *Group) spacing() int {
+	if g.Spacing == 0 {
+		return DefaultSpacing
+	}
+	return g.Spacing
+}
+
+// top returns the space above the children, ie. room for the
+// title.
+func (g *Group) top() int {
+	if g.Title == "" {
+		return g.Pad.Top
+	}
+	return g.Pad.Top + g.Font.LineHeight + g.Pad.Bottom
+}
+
+// layout places the group at x, y and its children inside it.
+func (g *Group) layout(x, y int) {
+	g.x, g.y = x, y
+	x += g.Pad.Left
+	y += g.top()
+	for _, c := range g.children {
+		if c, ok := c.(*Group); ok && c.Spacing == 0 {
+			c.Spacing = g.Spacing
+		}
+		c.SetX(x)
+		c.SetY(y)
+		if g.Vertical {
+			y += c.Height() + g.spacing()
+			continue
+		}
+		x += c.Width() + g.spacing()
+	}
+}
+
+// Width returns the width of the title or all children, whichever
+// is wider.
+func (g *Group) Width() int {
+	var w int
+	for i, c := range g.children {
+		switch {
+		case g.Vertical:
+			if c.Width() > w {
+				w = c.Width()
+			}
+		case i > 0:
+			w += g.spacing() + c.Width()
+		default:
+			w = c.Width()
+		}
+	}
+	if t := g.Font.TextWidth(g.Title); t > w {
+		w = t
+	}
+	return g.Pad.Left + w + g.Pad.Right
+}
+
+// Height returns the height of the title and all children.
+func (g *Group) Height() int {
+	var h int
+	for i, c := range g.children {
+		switch {
+		case !g.Vertical:
+			if c.Height() > h {
+				h = c.Height()
+			}
+		case i > 0:
+			h += g.spacing() + c.Height()
+		default:
+			h = c.Height()
+		}
+	}
+	return g.top() + h + g.Pad.Bottom
+}
+
+// Defs returns the definitions referred to by the group and its
+// children.
+func (g *Group) Defs() []draw.Def {
+	defs := g.Decoration.Defs()
+	for _, c := range g.children {
+		if c, ok := c.(draw.HasDefs); ok {
+			defs = append(defs, c.Defs()...)
+		}
+	}
+	return defs
+}
+
+// Edge returns intersecting position of a line starting at start and
+// pointing to the group center.
+func (g *Group) Edge(start xy.Point) xy.Point {
+	return boxEdge(start, g)
+}
+
+// WriteSVG writes the group frame and title followed by its children
+// inside a g element. Children are placed again in case Vertical or
+// Spacing changed.
+func (g *Group) WriteSVG(out io.Writer) error {
+	g.layout(g.x, g.y)
+	w, err := nexus.NewPrinter(out)
+	w.Printf("<g>\n")
+	w.Printf(
+		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"%s/>`,
+		g.class, g.x, g.y, g.Width(), g.Height(), g.rectAttrs())
+	w.Printf("\n")
+	if g.Title != "" {
+		title := &Label{
+			x:     g.x + g.Pad.Left,
+			y:     g.y + g.Pad.Top,
+			Font:  g.Font,
+			Text:  g.Title,
+			class: g.class + "-title",
+		}
+		title.WriteSVG(w)
+		w.Printf("\n")
+	}
+	for _, c := range g.children {
+		if *err != nil {
+			break
+		}
+		*err = c.WriteSVG(w)
+		w.Printf("\n")
+	}
+	w.Printf("</g>")
+	return *err
+}
@@ -0,0 +1,68 @@
+package shape
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/xy"
+)
+
+func TestGroup(t *testing.T) {
+	g := NewGroup("payments")
+	a, b := NewRect("a"), NewRect("b")
+	g.Add(a, b)
+	g.SetX(10)
+	g.SetY(20)
+
+	assert := asserter.New(t)
+	// children are inside the group, below the title
+	gx, gy := g.Position()
+	for _, c := range g.Children() {
+		x, y := c.Position()
+		assert(x > gx && x+c.Width() < gx+g.Width()).Errorf("%v outside in x", c)
+		assert(y >= gy+g.top() && y+c.Height() < gy+g.Height()).Errorf("%v outside in y", c)
+	}
+
+	// children move with the group
+	ax, ay := a.Position()
+	Move(g, 5, 7)
+	x, y := a.Position()
+	assert().Equals(x-ax, 5)
+	assert().Equals(y-ay, 7)
+
+	w, h := g.Width(), g.Height()
+	g.Vertical = true
+	assert(g.Width() < w).Error("vertical not narrower")
+	assert(g.Height() > h).Error("vertical not higher")
+
+	gx, gy = g.Position()
+	p := g.Edge(xy.Point{X: gx - 50, Y: gy + g.Height()/2})
+	assert().Equals(p.X, gx)
+
+	var buf bytes.Buffer
+	g.WriteSVG(&buf)
+	got := buf.String()
+	assert(strings.HasPrefix(got, "<g>")).Errorf("no g element\n%s", got)
+	assert(strings.HasSuffix(got, "</g>")).Errorf("g not closed\n%s", got)
+	assert().Contains(got, `class="group-title"`)
+	assert().Contains(got, `class="rect-title"`)
+	testShape(t, g)
+}
+
+func TestGroup_nested(t *testing.T) {
+	inner := NewGroup("")
+	inner.Add(NewRect("a"))
+	outer := NewGroup("outer")
+	outer.Spacing = 10
+	outer.Add(inner, NewRect("b"))
+	outer.SetX(100)
+
+	assert := asserter.New(t)
+	assert().Equals(inner.Spacing, 10)
+	ix, _ := inner.Position()
+	assert().Equals(ix, 100+outer.Pad.Left)
+	// untitled groups have no room for a title
+	assert().Equals(inner.Height(), inner.Pad.Top+NewRect("a").Height()+inner.Pad.Bottom)
+}
@@ -36,6 +36,9 @@ type Hexagon struct {
 	width, height, radius int // radius is the left/right corner distance
 
 	textAlign string
+
+	Ports
+	Decoration
 }
 
 func (r *Hexagon) String() string {
@@ -55,7 +58,7 @@ func (r *Hexagon) WriteSVG(out io.Writer) error {
 	hlen := r.width - 2*rad
 	h2 := r.height / 2
 
-	w.Printf(`<path class="%s" d="M%v,%v l %v,%v %v,%v %v,%v %v,%v %v,%v %v,%v" />`,
+	w.Printf(`<path class="%s" d="M%v,%v l %v,%v %v,%v %v,%v %v,%v %v,%v %v,%v"%s />`,
 		r.class,
 		x+rad, y,
 		hlen, 0,
@@ -64,6 +67,7 @@ func (r *Hexagon) WriteSVG(out io.Writer) error {
 		-hlen, 0,
 		-rad, -h2, // far left corner
 		rad, -h2,
+		r.attrs(),
 	)
 
 	w.Printf("\n")
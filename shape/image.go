@@ -0,0 +1,213 @@
+package shape
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gregoryv/draw"
+	"github.com/gregoryv/draw/xy"
+	"github.com/gregoryv/nexus"
+)
+
+// NewImage returns an image referring to href, e.g. a url, with the
+// given size.
+func NewImage(href string, width, height int) *Image {
+	return &Image{
+		Href:   href,
+		width:  width,
+		height: height,
+		class:  "image",
+	}
+}
+
+// LoadImage returns the PNG, JPEG or SVG file embedded as a data URI
+// with the intrinsic size of the image.
+func LoadImage(filename string) (*Image, error) {
+	data, mime, w, h, err := readImage(filename)
+	if err != nil {
+		return nil, err
+	}
+	href := "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data)
+	return NewImage(href, w, h), nil
+}
+
+// LinkImage returns an image referring to the PNG, JPEG or SVG file
+// by its name with the intrinsic size of the image. The file must
+// be found relative to the written SVG.
+func LinkImage(filename string) (*Image, error) {
+	_, _, w, h, err := readImage(filename)
+	if err != nil {
+		return nil, err
+	}
+	return NewImage(filepath.ToSlash(filename), w, h), nil
+}
+
+// Image is a picture, e.g. a logo, scaled to fit its size keeping
+// the aspect ratio.
+type Image struct {
+	x, y int
+
+	// Href is a data URI or a link to the image.
+	Href string
+
+	width, height int
+	class         string
+
+	Decoration
+}
+
+func (i *Image) String() string {
+	return fmt.Sprintf("Image %vx%v", i.width, i.height)
+}
+
+func (i *Image) Position() (int, int) { return i.x, i.y }
+func (i *Image) SetX(x int)           { i.x = x }
+func (i *Image) SetY(y int)           { i.y = y }
+func (i *Image) Width() int           { return i.width }
+func (i *Image) Height() int          { return i.height }
+func (i *Image) Direction() Direction { return DirectionRight }
+func (i *Image) SetClass(c string)    { i.class = c }
+
+// SetWidth sets the width and scales the height to keep the aspect
+// ratio.
+func (i *Image) SetWidth(w int) {
+	if i.width > 0 {
+		i.height = int(math.Round(float64(i.height*w) / float64(i.width)))
+	}
+	i.width = w
+}
+
+// SetHeight sets the height and scales the width to keep the aspect
+// ratio.
+func (i *Image) SetHeight(h int) {
+	if i.height > 0 {
+		i.width = int(math.Round(float64(i.width*h) / float64(i.height)))
+	}
+	i.height = h
+}
+
+func (i *Image) WriteSVG(out io.Writer) error {
+	w, err := nexus.NewPrinter(out)
+	w.Printf(`<image class="%s" x="%v" y="%v" width="%v" height="%v" xlink:href="%s"%s/>`,
+		i.class, i.x, i.y, i.width, i.height, html.EscapeString(i.Href), i.attrs())
+	return *err
+}
+
+// Edge returns intersecting position of a line starting at start and
+// pointing to the image center.
+func (i *Image) Edge(start xy.Point) xy.Point {
+	return boxEdge(start, i)
+}
+
+// iconDefs returns the definitions referred to by the decoration of
+// a shape and its icon, if any.
+func iconDefs(d *Decoration, icon *Image) []draw.Def {
+	defs := d.Defs()
+	if icon != nil {
+		defs = append(defs, icon.Defs()...)
+	}
+	return defs
+}
+
+// iconGap is the space between an icon and the title next to it.
+const iconGap = 4
+
+// iconSize returns the width and height added to a box with one line
+// of text by an icon left of the text, zero if icon is nil.
+func iconSize(icon *Image, font Font, pad Padding) (int, int) {
+	if icon == nil {
+		return 0, 0
+	}
+	dh := pad.Top + icon.Height() + pad.Bottom - boxHeight(font, pad, 1)
+	if dh < 0 {
+		dh = 0
+	}
+	return icon.Width() + iconGap, dh
+}
+
+// writeIcon writes the icon at x vertically centered in the height h
+// starting at y.
+func writeIcon(out io.Writer, icon *Image, x, y, h int) error {
+	icon.SetX(x)
+	icon.SetY(y + (h-icon.Height())/2)
+	return icon.WriteSVG(out)
+}
+
+// readImage returns the content, mime type and intrinsic size of the
+// image file.
+func readImage(filename string) (data []byte, mime string, w, h int, err error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".png":
+		mime = "image/png"
+	case ".jpg", ".jpeg":
+		mime = "image/jpeg"
+	case ".svg":
+		mime = "image/svg+xml"
+	default:
+		err = fmt.Errorf("%s: unsupported image type", filename)
+		return
+	}
+	data, err = ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	if mime == "image/svg+xml" {
+		w, h, err = svgSize(data)
+	} else {
+		var c image.Config
+		c, _, err = image.DecodeConfig(bytes.NewReader(data))
+		w, h = c.Width, c.Height
+	}
+	if err != nil {
+		err = fmt.Errorf("%s: %w", filename, err)
+	}
+	return
+}
+
+// svgSize returns the width and height of the svg root element, or
+// of its viewBox if the size is not given.
+func svgSize(data []byte) (int, int, error) {
+	var root struct {
+		Width   string `xml:"width,attr"`
+		Height  string `xml:"height,attr"`
+		ViewBox string `xml:"viewBox,attr"`
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return 0, 0, err
+	}
+	w, h := length(root.Width), length(root.Height)
+	if box := strings.Fields(strings.ReplaceAll(root.ViewBox, ",", " ")); len(box) == 4 {
+		if w == 0 {
+			w = length(box[2])
+		}
+		if h == 0 {
+			h = length(box[3])
+		}
+	}
+	if w == 0 || h == 0 {
+		return 0, 0, fmt.Errorf("missing svg size")
+	}
+	return w, h, nil
+}
+
+// length returns the rounded value of an svg length in pixels, zero
+// if it is not a number, e.g. a percentage.
+func length(v string) int {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(v, "px"), 64)
+	if err != nil {
+		return 0
+	}
+	return int(math.Round(f))
+}
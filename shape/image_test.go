@@ -0,0 +1,87 @@
+package shape
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/xy"
+)
+
+func TestLoadImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pngFile := filepath.Join(dir, "a.png")
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 20, 10)))
+	ioutil.WriteFile(pngFile, buf.Bytes(), 0644)
+	svgFile := filepath.Join(dir, "b.svg")
+	ioutil.WriteFile(svgFile, []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 32 16"></svg>`), 0644)
+
+	assert := asserter.New(t)
+	img, err := LoadImage(pngFile)
+	assert(err == nil).Fatal(err)
+	assert().Equals(img.Width(), 20)
+	assert().Equals(img.Height(), 10)
+	assert(strings.HasPrefix(img.Href, "data:image/png;base64,")).Error(img.Href)
+
+	img, err = LinkImage(svgFile)
+	assert(err == nil).Fatal(err)
+	assert().Equals(img.Width(), 32)
+	assert().Equals(img.Height(), 16)
+	assert().Equals(img.Href, filepath.ToSlash(svgFile))
+
+	img.SetHeight(8)
+	assert().Equals(img.Width(), 16)
+
+	_, err = LoadImage(filepath.Join(dir, "a.gif"))
+	assert(err != nil).Error("loaded unsupported type")
+	_, err = LoadImage(filepath.Join(dir, "missing.png"))
+	assert(err != nil).Error("loaded missing file")
+}
+
+func TestImage(t *testing.T) {
+	img := NewImage("logo.png", 16, 16)
+	got := img.Edge(xy.Point{X: 100, Y: 8})
+	assert := asserter.New(t)
+	assert().Equals(got, xy.Point{X: 16, Y: 8})
+
+	img.Href = `logo.png?a=1&b="<2>"`
+	var buf bytes.Buffer
+	img.WriteSVG(&buf)
+	assert().Contains(buf.String(),
+		`xlink:href="logo.png?a=1&amp;b=&#34;&lt;2&gt;&#34;"`)
+	testShape(t, img)
+}
+
+func TestRect_Icon(t *testing.T) {
+	r := NewRect("aws")
+	w, h := r.Width(), r.Height()
+	r.Icon = NewImage("logo.png", 32, 32)
+	assert := asserter.New(t)
+	assert().Equals(r.Width(), w+32+iconGap)
+	assert(r.Height() > h).Error("rect not higher than icon")
+	var buf bytes.Buffer
+	r.WriteSVG(&buf)
+	assert().Contains(buf.String(), `xlink:href="logo.png"`)
+
+	c := NewComponent("s3")
+	w = c.Width()
+	c.Icon = NewImage("logo.png", 16, 16)
+	assert().Equals(c.Width(), w+16+iconGap)
+
+	// definitions of icon decorations are included
+	r.Icon.Shadow = true
+	c.Icon.Shadow = true
+	assert().Equals(len(r.Defs()), 1)
+	assert().Equals(len(c.Defs()), 1)
+}
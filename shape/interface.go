@@ -0,0 +1,115 @@
+package shape
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gregoryv/draw/xy"
+	"github.com/gregoryv/nexus"
+)
+
+// NewLollipop returns a provided interface, a stick ending in a
+// ball, with the name below it.
+func NewLollipop(name string) *Interface {
+	return &Interface{
+		Name:   name,
+		Radius: 6,
+		Font:   DefaultFont,
+		Pad:    DefaultTextPad,
+		class:  "lollipop",
+	}
+}
+
+// NewSocket returns a required interface, a stick ending in an open
+// half circle, with the name below it.
+func NewSocket(name string) *Interface {
+	return &Interface{
+		Name:     name,
+		Radius:   8,
+		Required: true,
+		Font:     DefaultFont,
+		Pad:      DefaultTextPad,
+		class:    "socket",
+	}
+}
+
+// Interface is a provided or required interface marker. The stick
+// starts at the left side, connect it to e.g. a component.
+type Interface struct {
+	x, y int
+	Name string
+
+	// Required draws a socket instead of a ball
+	Required bool
+
+	Radius int
+
+	Font  Font
+	Pad   Padding
+	class string
+
+	Decoration
+}
+
+// stick is the length of the line before the ball or socket.
+const stick = 20
+
+func (i *Interface) String() string {
+	if i.Required {
+		return fmt.Sprintf("Socket %q", i.Name)
+	}
+	return fmt.Sprintf("Lollipop %q", i.Name)
+}
+
+func (i *Interface) Position() (int, int) { return i.x, i.y }
+func (i *Interface) SetX(x int)           { i.x = x }
+func (i *Interface) SetY(y int)           { i.y = y }
+func (i *Interface) Direction() Direction { return DirectionRight }
+func (i *Interface) SetClass(c string)    { i.class = c }
+func (i *Interface) SetFont(f Font)       { i.Font = f }
+
+func (i *Interface) SetTextPad(pad Padding) { i.Pad = pad }
+
+func (i *Interface) WriteSVG(out io.Writer) error {
+	w, err := nexus.NewPrinter(out)
+	r := i.Radius
+	cx, cy := i.x+stick+r, i.y+r
+	if i.Required {
+		// the socket opens away from the stick
+		w.Printf(`<path class="%s" d="M%v,%v h%v M%v,%v A%v,%v 0 0,0 %v,%v"%s/>`,
+			i.class, i.x, cy, stick, cx, i.y, r, r, cx, cy+r, i.attrs())
+	} else {
+		w.Printf(`<path class="%s" d="M%v,%v h%v"%s/>`,
+			i.class, i.x, cy, stick, i.attrs())
+		w.Printf("\n")
+		w.Printf(`<circle class="%s" cx="%v" cy="%v" r="%v"%s/>`,
+			i.class, cx, cy, r, i.attrs())
+	}
+	w.Printf("\n")
+	name := &Label{
+		x:     i.x + i.Pad.Left,
+		y:     i.y + 2*r + i.Pad.Top,
+		Font:  i.Font,
+		Text:  i.Name,
+		class: i.class + "-title",
+	}
+	name.WriteSVG(w)
+	return *err
+}
+
+func (i *Interface) Width() int {
+	w := stick + 2*i.Radius
+	if t := boxWidth(i.Font, i.Pad, i.Name); t > w {
+		return t
+	}
+	return w
+}
+
+func (i *Interface) Height() int {
+	return 2*i.Radius + boxHeight(i.Font, i.Pad, 1)
+}
+
+// Edge returns the start of the stick, regardless of start.
+func (i *Interface) Edge(start xy.Point) xy.Point {
+	return xy.Point{X: i.x, Y: i.y + i.Radius}
+}
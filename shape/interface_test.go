@@ -0,0 +1,27 @@
+package shape
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gregoryv/asserter"
+)
+
+func TestInterface_SetTextPad(t *testing.T) {
+	i := NewLollipop("io.Reader")
+	i.SetTextPad(Padding{Left: 1, Top: 2, Right: 3, Bottom: 4})
+	assert := asserter.New(t)
+	assert().Equals(i.Width(), 1+i.Font.TextWidth(i.Name)+3)
+	assert().Equals(i.Height(), 2*i.Radius+2+i.Font.LineHeight+4)
+
+	i.Shadow = true
+	var buf bytes.Buffer
+	i.WriteSVG(&buf)
+	got := buf.String()
+	// name below the ball, moved by the padding
+	assert().Contains(got, `x="1" y="30"`)
+	if strings.Count(got, `filter="url(#shadow)"`) != 2 {
+		t.Error("decoration not applied to stick and ball\n", got)
+	}
+}
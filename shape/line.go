@@ -20,6 +20,8 @@ type Line struct {
 	End   xy.Point
 
 	class string
+
+	Decoration
 }
 
 func (l *Line) String() string {
@@ -28,10 +30,11 @@ func (l *Line) String() string {
 
 func (l *Line) WriteSVG(w io.Writer) error {
 	_, err := fmt.Fprintf(w,
-		`<line class="%s" x1="%v" y1="%v" x2="%v" y2="%v"/>`,
+		`<line class="%s" x1="%v" y1="%v" x2="%v" y2="%v"%s/>`,
 		l.class,
 		l.Start.X, l.Start.Y,
 		l.End.X, l.End.Y,
+		l.attrs(),
 	)
 	return err
 }
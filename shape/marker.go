@@ -0,0 +1,98 @@
+package shape
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+
+	"github.com/gregoryv/draw"
+	"github.com/gregoryv/nexus"
+)
+
+// marker is an arrow head or tail drawn by the renderer at the end of
+// a path, rotated to follow its direction.
+type marker struct {
+	id  string
+	end Shape
+}
+
+// newMarker returns a marker of a copy of the shape drawn as if the
+// path ends at 0,0 pointing to the right. The id depends on the
+// drawing so equal heads share one marker.
+func newMarker(end Shape, class string, tail bool) *marker {
+	end = cloneShape(end)
+	end.SetClass(class)
+	if tail {
+		alignTail(end, 0, 0)
+	} else {
+		alignHead(end, 0, 0)
+	}
+	var buf bytes.Buffer
+	end.WriteSVG(&buf)
+	h := fnv.New32a()
+	h.Write(buf.Bytes())
+	return &marker{
+		id:  fmt.Sprintf("%s-%x", class, h.Sum32()),
+		end: end,
+	}
+}
+
+// cloneShape returns a shallow copy of s, or s if it's not a pointer
+// to a struct.
+func cloneShape(s Shape) Shape {
+	v := reflect.ValueOf(s)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return s
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+	return c.Interface().(Shape)
+}
+
+func (m *marker) DefID() string { return m.id }
+
+// WriteSVG writes the marker, the shape is written directly to out
+// so its class can be styled.
+func (m *marker) WriteSVG(out io.Writer) error {
+	w, err := nexus.NewPrinter(out)
+	w.Printf(`<marker id="%s" markerUnits="userSpaceOnUse" orient="auto" overflow="visible">`, m.id)
+	if *err != nil {
+		return *err
+	}
+	if e := m.end.WriteSVG(out); e != nil {
+		return e
+	}
+	w.Print("</marker>")
+	return *err
+}
+
+// markers returns the head and tail markers of the arrow, nil if it
+// has none.
+func (a *Arrow) markers() (head, tail *marker) {
+	if a.Head != nil {
+		head = newMarker(a.Head, a.class+"-head", false)
+	}
+	if a.Tail != nil {
+		tail = newMarker(a.Tail, a.class+"-tail", true)
+	}
+	return
+}
+
+// Defs returns the definitions referred to by the arrow, ie. its
+// decoration and markers.
+func (a *Arrow) Defs() []draw.Def {
+	defs := a.Decoration.Defs()
+	if !a.Markers {
+		return defs
+	}
+	head, tail := a.markers()
+	if head != nil {
+		defs = append(defs, head)
+	}
+	if tail != nil {
+		defs = append(defs, tail)
+	}
+	return defs
+}
@@ -0,0 +1,89 @@
+package shape
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gregoryv/draw/xy"
+	"github.com/gregoryv/nexus"
+)
+
+// NewNode returns a UML node, a three dimensional box for e.g. a
+// device or execution environment.
+func NewNode(title string) *Node {
+	return &Node{
+		Title: title,
+		Font:  DefaultFont,
+		Pad:   DefaultTextPad,
+		Depth: 10,
+		class: "node",
+	}
+}
+
+type Node struct {
+	x, y  int
+	Title string
+
+	// Depth of the top and right side
+	Depth int
+
+	Font  Font
+	Pad   Padding
+	class string
+
+	Ports
+	Decoration
+}
+
+func (n *Node) String() string {
+	return fmt.Sprintf("Node %q", n.Title)
+}
+
+func (n *Node) Position() (int, int) { return n.x, n.y }
+func (n *Node) SetX(x int)           { n.x = x }
+func (n *Node) SetY(y int)           { n.y = y }
+func (n *Node) Direction() Direction { return DirectionRight }
+func (n *Node) SetClass(c string)    { n.class = c }
+
+func (n *Node) SetFont(f Font)         { n.Font = f }
+func (n *Node) SetTextPad(pad Padding) { n.Pad = pad }
+
+func (n *Node) WriteSVG(out io.Writer) error {
+	w, err := nexus.NewPrinter(out)
+	x, y, d := n.x, n.y, n.Depth
+	width, height := n.Width(), n.Height()
+	// front face followed by the top and right side
+	w.Printf(
+		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"%s/>`,
+		n.class, x, y+d, width-d, height-d, n.attrs())
+	w.Printf("\n")
+	w.Printf(`<path class="%s" d="M%v,%v l%v,%v h%v l%v,%v Z"%s/>`,
+		n.class, x, y+d, d, -d, width-d, -d, d, n.attrs())
+	w.Printf("\n")
+	w.Printf(`<path class="%s" d="M%v,%v l%v,%v v%v l%v,%v Z"%s/>`,
+		n.class, x+width-d, y+d, d, -d, height-d, -d, d, n.attrs())
+	w.Printf("\n")
+	title := &Label{
+		x:     x + n.Pad.Left,
+		y:     y + d + n.Pad.Top/2,
+		Font:  n.Font,
+		Text:  n.Title,
+		class: n.class + "-title",
+	}
+	title.WriteSVG(w)
+	return *err
+}
+
+func (n *Node) Width() int {
+	return boxWidth(n.Font, n.Pad, n.Title) + n.Depth
+}
+
+func (n *Node) Height() int {
+	return boxHeight(n.Font, n.Pad, 1) + n.Depth
+}
+
+// Edge returns intersecting position of a line starting at start and
+// pointing to the node center.
+func (n *Node) Edge(start xy.Point) xy.Point {
+	return boxEdge(start, n)
+}
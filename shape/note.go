@@ -25,6 +25,9 @@ type Note struct {
 	Font
 	Pad   Padding
 	class string
+
+	Ports
+	Decoration
 }
 
 func (n *Note) String() string {
@@ -70,8 +73,8 @@ func (n *Note) WriteSVG(out io.Writer) error {
 	   y+h               x+w
 	*/
 	t.Printf(`<path class="%s-box" d="M%v,%v `, n.class, x, y)
-	t.Printf(`v %v h %v v %v l %v,%v L %v,%v M%v,%v h %v v %v"/>`,
-		h, w, -(h - flap), -flap, -flap, x, y, x+w, y+flap, -flap, -flap)
+	t.Printf(`v %v h %v v %v l %v,%v L %v,%v M%v,%v h %v v %v"%s/>`,
+		h, w, -(h - flap), -flap, -flap, x, y, x+w, y+flap, -flap, -flap, n.attrs())
 	t.Print("\n")
 	x += n.Pad.Left
 	for i, line := range strings.Split(n.Text, "\n") {
@@ -30,6 +30,16 @@ func intAbs(v int) int {
 	return int(math.Abs(float64(v)))
 }
 
+func maxInt(v ...int) int {
+	m := v[0]
+	for _, v := range v[1:] {
+		if v > m {
+			m = v
+		}
+	}
+	return m
+}
+
 type Edge interface {
 	// Edge returns the intersecting position to a shape from start
 	// position.
@@ -26,6 +26,9 @@ type Record struct {
 	Font  Font
 	Pad   Padding
 	class string
+
+	Ports
+	Decoration
 }
 
 func (r *Record) String() string {
@@ -41,8 +44,8 @@ func (r *Record) SetClass(c string)    { r.class = c }
 func (r *Record) WriteSVG(out io.Writer) error {
 	w, err := nexus.NewPrinter(out)
 	w.Printf(
-		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"/>`,
-		r.class, r.X, r.Y, r.Width(), r.Height())
+		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"%s/>`,
+		r.class, r.X, r.Y, r.Width(), r.Height(), r.rectAttrs())
 	w.Printf("\n")
 	var y = boxHeight(r.Font, r.Pad, 1)
 	hasFields := len(r.Fields) != 0
@@ -5,6 +5,7 @@ import (
 	"io"
 	"math"
 
+	"github.com/gregoryv/draw"
 	"github.com/gregoryv/draw/xy"
 	"github.com/gregoryv/nexus"
 )
@@ -29,6 +30,12 @@ type Rect struct {
 	width, height int
 
 	textAlign string
+
+	// Icon is drawn left of the title, e.g. a logo.
+	Icon *Image
+
+	Ports
+	Decoration
 }
 
 func (r *Rect) String() string {
@@ -41,20 +48,30 @@ func (r *Rect) SetY(y int)           { r.Y = y }
 func (r *Rect) Direction() Direction { return DirectionRight }
 func (r *Rect) SetClass(c string)    { r.class = c }
 
+// Defs returns the definitions referred to by the decoration of the
+// rect and its icon.
+func (r *Rect) Defs() []draw.Def { return iconDefs(&r.Decoration, r.Icon) }
+
 func (r *Rect) WriteSVG(out io.Writer) error {
 	w, err := nexus.NewPrinter(out)
 	w.Printf(
-		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"/>`,
-		r.class, r.X, r.Y, r.Width(), r.Height())
+		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"%s/>`,
+		r.class, r.X, r.Y, r.Width(), r.Height(), r.rectAttrs())
 	w.Printf("\n")
+	if r.Icon != nil {
+		_, dh := iconSize(r.Icon, r.Font, r.Pad)
+		writeIcon(w, r.Icon, r.X+r.Pad.Left, r.Y, boxHeight(r.Font, r.Pad, 1)+dh)
+		w.Printf("\n")
+	}
 	r.title().WriteSVG(w)
 	return *err
 }
 
 func (r *Rect) title() *Label {
+	dw, dh := iconSize(r.Icon, r.Font, r.Pad)
 	return &Label{
-		x:     r.X + r.Pad.Left,
-		y:     r.Y + r.Pad.Top/2,
+		x:     r.X + r.Pad.Left + dw,
+		y:     r.Y + r.Pad.Top/2 + dh/2,
 		Font:  r.Font,
 		Text:  r.Title,
 		class: r.class + "-title",
@@ -68,14 +85,16 @@ func (r *Rect) Height() int {
 	if r.height > 0 {
 		return r.height
 	}
-	return boxHeight(r.Font, r.Pad, 1)
+	_, dh := iconSize(r.Icon, r.Font, r.Pad)
+	return boxHeight(r.Font, r.Pad, 1) + dh
 }
 
 func (r *Rect) Width() int {
 	if r.width > 0 {
 		return r.width
 	}
-	return boxWidth(r.Font, r.Pad, r.Title)
+	dw, _ := iconSize(r.Icon, r.Font, r.Pad)
+	return boxWidth(r.Font, r.Pad, r.Title) + dw
 }
 
 func (r *Rect) SetWidth(w int)  { r.width = w }
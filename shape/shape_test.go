@@ -30,6 +30,12 @@ func TestSaveShapes(t *testing.T) {
 		NewDecision(),
 		NewActor(),
 		NewInternet(),
+		NewPackage("pkg"),
+		NewLollipop("Reader"),
+		NewSocket("Writer"),
+		NewNode("server"),
+		NewArtifact("app.jar"),
+		NewUseCase("Place\norder"),
 	}
 	for _, shape := range shapes {
 		img := draw.NewSVG()
@@ -65,6 +71,12 @@ func TestShapes(t *testing.T) {
 		NewState("Waiting for push"),
 		NewDecision(),
 		NewActor(),
+		NewPackage("pkg"),
+		NewLollipop("Reader"),
+		NewSocket("Writer"),
+		NewNode("server"),
+		NewArtifact("app.jar"),
+		NewUseCase("Place\norder"),
 		r,
 	}
 	for _, shape := range shapes {
@@ -80,8 +92,8 @@ func testShape(t *testing.T, shape Shape) {
 		Move(shape, 1, 1)
 		assert := asserter.New(t)
 		x1, y1 := shape.Position()
-		assert(x != x1).Errorf("x same as before move")
-		assert(y != y1).Errorf("y same as before move")
+		assert(x1 == x+1).Errorf("x moved %v", x1-x)
+		assert(y1 == y+1).Errorf("y moved %v", y1-y)
 	})
 
 	t.Run("Has direction", func(t *testing.T) {
@@ -24,6 +24,9 @@ type State struct {
 	Font  Font
 	Pad   Padding
 	class string
+
+	Ports
+	Decoration
 }
 
 func (r *State) String() string {
@@ -39,8 +42,8 @@ func (r *State) SetClass(c string)    { r.class = c }
 func (r *State) WriteSVG(out io.Writer) error {
 	w, err := nexus.NewPrinter(out)
 	w.Printf(
-		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"/>`,
-		r.class, r.X, r.Y, r.Width(), r.Height())
+		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"%s/>`,
+		r.class, r.X, r.Y, r.Width(), r.Height(), r.rectAttrs())
 	w.Printf("\n")
 	r.title().WriteSVG(w)
 	return *err
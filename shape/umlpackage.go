@@ -0,0 +1,90 @@
+package shape
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gregoryv/draw/xy"
+	"github.com/gregoryv/nexus"
+)
+
+// NewPackage returns a UML package, a folder with a tab.
+func NewPackage(title string) *Package {
+	return &Package{
+		Title: title,
+		Font:  DefaultFont,
+		Pad:   DefaultTextPad,
+		class: "package",
+	}
+}
+
+type Package struct {
+	x, y  int
+	Title string
+
+	Font  Font
+	Pad   Padding
+	class string
+
+	Ports
+	Decoration
+}
+
+func (p *Package) String() string {
+	return fmt.Sprintf("Package %q", p.Title)
+}
+
+func (p *Package) Position() (int, int) { return p.x, p.y }
+func (p *Package) SetX(x int)           { p.x = x }
+func (p *Package) SetY(y int)           { p.y = y }
+func (p *Package) Direction() Direction { return DirectionRight }
+func (p *Package) SetClass(c string)    { p.class = c }
+
+func (p *Package) SetFont(f Font)         { p.Font = f }
+func (p *Package) SetTextPad(pad Padding) { p.Pad = pad }
+
+func (p *Package) WriteSVG(out io.Writer) error {
+	w, err := nexus.NewPrinter(out)
+	tw, th := p.tab()
+	w.Printf(
+		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"%s/>`,
+		p.class, p.x, p.y, tw, th, p.attrs())
+	w.Printf("\n")
+	w.Printf(
+		`<rect class="%s" x="%v" y="%v" width="%v" height="%v"%s/>`,
+		p.class, p.x, p.y+th, p.Width(), p.Height()-th, p.rectAttrs())
+	w.Printf("\n")
+	title := &Label{
+		x:     p.x + p.Pad.Left,
+		y:     p.y + th + p.Pad.Top/2,
+		Font:  p.Font,
+		Text:  p.Title,
+		class: p.class + "-title",
+	}
+	title.WriteSVG(w)
+	return *err
+}
+
+// tab returns the width and height of the tab.
+func (p *Package) tab() (int, int) {
+	return p.Width() * 2 / 5, p.Font.LineHeight / 2
+}
+
+func (p *Package) Width() int {
+	w := boxWidth(p.Font, p.Pad, p.Title)
+	if w < 50 {
+		return 50
+	}
+	return w
+}
+
+func (p *Package) Height() int {
+	_, th := p.tab()
+	return th + boxHeight(p.Font, p.Pad, 1)
+}
+
+// Edge returns intersecting position of a line starting at start and
+// pointing to the package center.
+func (p *Package) Edge(start xy.Point) xy.Point {
+	return boxEdge(start, p)
+}
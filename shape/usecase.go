@@ -0,0 +1,115 @@
+package shape
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/gregoryv/draw/xy"
+	"github.com/gregoryv/nexus"
+)
+
+// NewUseCase returns an ellipse fitting the given, possibly
+// multiline, title.
+func NewUseCase(title string) *UseCase {
+	return &UseCase{
+		Title: title,
+		Font:  DefaultFont,
+		Pad:   DefaultTextPad,
+		class: "usecase",
+	}
+}
+
+type UseCase struct {
+	x, y  int
+	Title string
+
+	Font  Font
+	Pad   Padding
+	class string
+
+	Ports
+	Decoration
+}
+
+func (u *UseCase) String() string {
+	return fmt.Sprintf("UseCase %q", u.Title)
+}
+
+func (u *UseCase) Position() (int, int) { return u.x, u.y }
+func (u *UseCase) SetX(x int)           { u.x = x }
+func (u *UseCase) SetY(y int)           { u.y = y }
+func (u *UseCase) Direction() Direction { return DirectionRight }
+func (u *UseCase) SetClass(c string)    { u.class = c }
+
+func (u *UseCase) SetFont(f Font)         { u.Font = f }
+func (u *UseCase) SetTextPad(pad Padding) { u.Pad = pad }
+
+func (u *UseCase) WriteSVG(out io.Writer) error {
+	w, err := nexus.NewPrinter(out)
+	rx, ry := u.radius()
+	w.Printf(`<ellipse class="%s" cx="%v" cy="%v" rx="%v" ry="%v"%s/>`,
+		u.class, u.x+u.Width()/2, u.y+u.Height()/2, rx, ry, u.attrs())
+	lines := u.lines()
+	y := u.y + (u.Height()-len(lines)*u.Font.LineHeight)/2
+	for _, txt := range lines {
+		w.Printf("\n")
+		label := &Label{
+			x:     u.x + (u.Width()-u.Font.TextWidth(txt))/2,
+			y:     y,
+			Font:  u.Font,
+			Text:  txt,
+			class: u.class + "-title",
+		}
+		label.WriteSVG(w)
+		y += u.Font.LineHeight
+	}
+	return *err
+}
+
+func (u *UseCase) lines() []string {
+	return strings.Split(u.Title, "\n")
+}
+
+// radius returns the radii of the ellipse surrounding the text box,
+// which touches the ellipse in its corners.
+func (u *UseCase) radius() (int, int) {
+	var tw int
+	lines := u.lines()
+	for _, txt := range lines {
+		if w := u.Font.TextWidth(txt); w > tw {
+			tw = w
+		}
+	}
+	th := len(lines) * u.Font.LineHeight
+	rx := float64(tw/2+u.Pad.Left) * math.Sqrt2
+	ry := float64(th/2+u.Pad.Top) * math.Sqrt2
+	return int(math.Round(rx)), int(math.Round(ry))
+}
+
+func (u *UseCase) Width() int {
+	rx, _ := u.radius()
+	return 2 * rx
+}
+
+func (u *UseCase) Height() int {
+	_, ry := u.radius()
+	return 2 * ry
+}
+
+// Edge returns intersecting position of a line starting at start and
+// pointing to the ellipse center.
+func (u *UseCase) Edge(start xy.Point) xy.Point {
+	rx, ry := u.radius()
+	c := xy.Point{X: u.x + rx, Y: u.y + ry}
+	dx, dy := float64(start.X-c.X), float64(start.Y-c.Y)
+	if dx == 0 && dy == 0 {
+		return c
+	}
+	t := 1 / math.Sqrt(dx*dx/float64(rx*rx)+dy*dy/float64(ry*ry))
+	return xy.Point{
+		X: c.X + int(math.Round(t*dx)),
+		Y: c.Y + int(math.Round(t*dy)),
+	}
+}
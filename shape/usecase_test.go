@@ -0,0 +1,37 @@
+package shape
+
+import (
+	"testing"
+
+	"github.com/gregoryv/asserter"
+	"github.com/gregoryv/draw/xy"
+)
+
+func TestUseCase_Edge(t *testing.T) {
+	u := NewUseCase("Place\norder")
+	u.SetX(10)
+	u.SetY(10)
+	rx, ry := u.radius()
+	cx, cy := 10+rx, 10+ry
+	assert := asserter.New(t)
+	ok := func(start, exp xy.Point) {
+		t.Helper()
+		got := u.Edge(start)
+		assert(got == exp).Errorf("Edge(%v) = %v, expected %v", start, got, exp)
+	}
+	ok(xy.Point{X: cx - 100, Y: cy}, xy.Point{X: 10, Y: cy})
+	ok(xy.Point{X: cx + 100, Y: cy}, xy.Point{X: cx + rx, Y: cy})
+	ok(xy.Point{X: cx, Y: cy - 100}, xy.Point{X: cx, Y: 10})
+	ok(xy.Point{X: cx, Y: cy}, xy.Point{X: cx, Y: cy})
+}
+
+func TestInterface_Edge(t *testing.T) {
+	i := NewLollipop("Reader")
+	i.SetX(5)
+	i.SetY(5)
+	got := i.Edge(xy.Point{X: 100, Y: 100})
+	exp := xy.Point{X: 5, Y: 5 + i.Radius}
+	if got != exp {
+		t.Errorf("got %v, expected %v", got, exp)
+	}
+}
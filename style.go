@@ -68,6 +68,16 @@ var DefaultClassAttributes = ClassAttributes{
 	"area-blue":        `stroke="black" stroke-width="0" fill="#99e6ff" fill-opacity="0.1"`,
 
 	"actor":                 `stroke="black" stroke-width="2" fill="#ffffff"`,
+	"actor-title":           `font-family="Arial,Helvetica,sans-serif"`,
+	"association":           `stroke="black"`,
+	"include-arrow":         `stroke="black" stroke-dasharray="5,5,5"`,
+	"include-arrow-head":    `stroke="black" fill="#ffffff"`,
+	"extend-arrow":          `stroke="black" stroke-dasharray="5,5,5"`,
+	"extend-arrow-head":     `stroke="black" fill="#ffffff"`,
+	"generalize-arrow":      `stroke="black"`,
+	"generalize-arrow-head": `stroke="black" fill="#ffffff"`,
+	"system":                `stroke="#777777" fill="#ffffff"`,
+	"system-title":          `font-family="Arial,Helvetica,sans-serif" font-weight="bold"`,
 	"circle":                `stroke="#d3d3d3" stroke-width="2" fill="#ffffff"`,
 	"cylinder":              `stroke="#d3d3d3" stroke-width="1" fill="#ffffff"`,
 	"database":              `stroke="#d3d3d3" stroke-width="1" fill="#ffffff"`,
@@ -94,9 +104,16 @@ var DefaultClassAttributes = ClassAttributes{
 	"aggregate-arrow-head":  `stroke="black" fill="#ffffff"`,
 	"aggregate-arrow-tail":  `stroke="black" fill="#ffffff"`,
 	"external":              `stroke="#d3d3d3" fill="#e2e2e2"`,
+	"external-title":        `font-family="Arial,Helvetica,sans-serif"`,
+	"cycle":                 `stroke="red" fill="#ffe6e6"`,
+	"cycle-title":           `font-family="Arial,Helvetica,sans-serif"`,
+	"cycle-arrow":           `stroke="red"`,
+	"cycle-arrow-head":      `stroke="red" fill="#ffffff"`,
 	"dim":                   `stroke="#d3d3d3" fill="#e2e2e2"`,
 	"hexagon":               `stroke="#d3d3d3" fill="#ffffff"`,
 	"hexagon-title":         `font-family="Arial,Helvetica,sans-serif"`,
+	"database-title":        `font-family="Arial,Helvetica,sans-serif"`,
+	"image":                 `preserveAspectRatio="xMidYMid meet"`,
 	"internet":              `stroke="#d3d3d3" fill="#e2e2e2"`,
 	"internet-title":        `font-family="Arial,Helvetica,sans-serif"`,
 	"line":                  `stroke="black"`,
@@ -105,6 +122,20 @@ var DefaultClassAttributes = ClassAttributes{
 	"record":                `stroke="#d3d3d3" fill="#ffffff"`,
 	"record-line":           `stroke="#d3d3d3"`,
 	"record-title":          `font-family="Arial,Helvetica,sans-serif"`,
+	"group":                 `stroke="#777777" fill="#ffffff"`,
+	"group-title":           `font-family="Arial,Helvetica,sans-serif" font-weight="bold"`,
+	"package":               `stroke="#d3d3d3" fill="#ffffff"`,
+	"package-title":         `font-family="Arial,Helvetica,sans-serif"`,
+	"node":                  `stroke="#d3d3d3" fill="#ffffff"`,
+	"node-title":            `font-family="Arial,Helvetica,sans-serif"`,
+	"artifact":              `stroke="#d3d3d3" fill="#ffffff"`,
+	"artifact-title":        `font-family="Arial,Helvetica,sans-serif"`,
+	"usecase":               `stroke="#d3d3d3" fill="#ffffff"`,
+	"usecase-title":         `font-family="Arial,Helvetica,sans-serif"`,
+	"lollipop":              `stroke="black" fill="#ffffff"`,
+	"lollipop-title":        `font-family="Arial,Helvetica,sans-serif"`,
+	"socket":                `stroke="black" fill="none"`,
+	"socket-title":          `font-family="Arial,Helvetica,sans-serif"`,
 	"rect":                  `stroke="#d3d3d3" fill="#ffffff"`,
 	"rect-title":            `font-family="Arial,Helvetica,sans-serif"`,
 	"root":                  `font-family="Arial,Helvetica,sans-serif"`, // root svg tag
@@ -119,8 +150,59 @@ var DefaultClassAttributes = ClassAttributes{
 	"span-yellow-title":     `font-family="Arial,Helvetica,sans-serif"`,
 	"span-orange":           `stroke="#d3d3d3" fill="#ffdf9e" rx="5" ry="5"`,
 	"span-orange-title":     `font-family="Arial,Helvetica,sans-serif"`,
+	"span-critical":         `stroke="#cc0000" fill="#ff9999" rx="5" ry="5"`,
+	"span-critical-title":   `font-family="Arial,Helvetica,sans-serif"`,
+	"critical-arrow":        `stroke="#cc0000"`,
+	"critical-arrow-head":   `stroke="#cc0000" fill="#cc0000"`,
+	"gantt-summary":         `stroke="black" fill="#333333"`,
+	"gantt-summary-title":   `font-family="Arial,Helvetica,sans-serif"`,
+	"gantt-progress":        `stroke="none" fill="#000000" fill-opacity="0.2" rx="5" ry="5"`,
+	"gantt-progress-title":  `font-family="Arial,Helvetica,sans-serif"`,
+	"gantt-arrow":           `stroke="#777777"`,
+	"gantt-arrow-head":      `stroke="#777777" fill="#777777"`,
+	"er-arrow":              `stroke="black"`,
+	"er-arrow-head":         `stroke="black" fill="#ffffff"`,
+	"er-arrow-tail":         `stroke="black" fill="#ffffff"`,
+	"crowfoot":              `stroke="black" fill="#ffffff"`,
+	"c4-person":             `stroke="#073b6f" fill="#08427b" rx="8" ry="8"`,
+	"c4-person-title":       `font-family="Arial,Helvetica,sans-serif" font-weight="bold" fill="#ffffff"`,
+	"c4-person-text":        `font-family="Arial,Helvetica,sans-serif" fill="#ffffff"`,
+	"c4-system":             `stroke="#0b4884" fill="#1168bd" rx="8" ry="8"`,
+	"c4-system-title":       `font-family="Arial,Helvetica,sans-serif" font-weight="bold" fill="#ffffff"`,
+	"c4-system-text":        `font-family="Arial,Helvetica,sans-serif" fill="#ffffff"`,
+	"c4-container":          `stroke="#3c7fc0" fill="#438dd5" rx="8" ry="8"`,
+	"c4-container-title":    `font-family="Arial,Helvetica,sans-serif" font-weight="bold" fill="#ffffff"`,
+	"c4-container-text":     `font-family="Arial,Helvetica,sans-serif" fill="#ffffff"`,
+	"c4-component":          `stroke="#78a8d8" fill="#85bbf0" rx="8" ry="8"`,
+	"c4-component-title":    `font-family="Arial,Helvetica,sans-serif" font-weight="bold" fill="#000000"`,
+	"c4-component-text":     `font-family="Arial,Helvetica,sans-serif" fill="#000000"`,
+	"c4-external":           `stroke="#8a8a8a" fill="#999999" rx="8" ry="8"`,
+	"c4-external-title":     `font-family="Arial,Helvetica,sans-serif" font-weight="bold" fill="#ffffff"`,
+	"c4-external-text":      `font-family="Arial,Helvetica,sans-serif" fill="#ffffff"`,
+	"c4-boundary":           `stroke="#444444" stroke-dasharray="8,4" fill="none"`,
+	"c4-boundary-title":     `font-family="Arial,Helvetica,sans-serif" font-weight="bold" fill="#444444"`,
+	"c4-rel":                `stroke="#707070" stroke-dasharray="6,3"`,
+	"c4-rel-head":           `stroke="#707070" fill="#707070"`,
+	"c4-tech":               `font-family="Arial,Helvetica,sans-serif" fill="#707070"`,
+	"deploy-node":           `stroke="#777777" fill="#ffffff"`,
+	"deploy-node-title":     `font-family="Arial,Helvetica,sans-serif" font-weight="bold"`,
+	"deploy-zone":           `stroke="#777777" stroke-dasharray="6,3" fill="#f3f3f3" rx="10" ry="10"`,
+	"deploy-zone-title":     `font-family="Arial,Helvetica,sans-serif" font-weight="bold"`,
+	"deploy-cluster":        `stroke="#777777" fill="#e6f2ff" rx="6" ry="6"`,
+	"deploy-cluster-title":  `font-family="Arial,Helvetica,sans-serif" font-weight="bold"`,
+	"gantt-lane":            `font-family="Arial,Helvetica,sans-serif" font-weight="bold"`,
+	"overallocated":         `stroke="#cc0000" stroke-width="2" fill="none" rx="5" ry="5"`,
+	"overallocated-title":   `font-family="Arial,Helvetica,sans-serif"`,
+	"gantt-load":            `stroke="none" fill="#99e6ff"`,
+	"gantt-load-title":      `font-family="Arial,Helvetica,sans-serif"`,
+	"gantt-overload":        `stroke="none" fill="#ff9999"`,
+	"gantt-overload-title":  `font-family="Arial,Helvetica,sans-serif"`,
+	"milestone":             `stroke="black" fill="#333333"`,
+	"milestone-title":       `font-family="Arial,Helvetica,sans-serif"`,
 	"state-title":           `font-family="Arial,Helvetica,sans-serif"`,
 	"state":                 `stroke="#d3d3d3" fill="#ffffff" rx="10" ry="10"`,
+	"composite":             `stroke="#d3d3d3" fill="#fafafa" rx="10" ry="10"`,
+	"composite-title":       `font-family="Arial,Helvetica,sans-serif"`,
 	"component":             `stroke="#d3d3d3" fill="#ffffff"`,
 	"component-title":       `font-family="Arial,Helvetica,sans-serif"`,
 	"field":                 `font-family="Arial,Helvetica,sans-serif"`,
@@ -129,9 +211,27 @@ var DefaultClassAttributes = ClassAttributes{
 	"label":                 `font-family="Arial,Helvetica,sans-serif"`,
 	"weekend":               `font-family="Arial,Helvetica,sans-serif" fill="#f3f3f3"`,
 	"weekend-title":         `font-family="Arial,Helvetica,sans-serif"`,
+	"mark":                  `stroke="red"`,
+	"holiday":               `fill="#ffe6cc"`,
+	"holiday-title":         `font-family="Arial,Helvetica,sans-serif"`,
 	"caption":               `font-family="Arial,Helvetica,sans-serif"`,
 	"diamond":               `stroke="#d3d3d3" fill="#333333"`,
 	"decision":              `stroke="#d3d3d3" fill="#ffffff"`,
+	"bar":                   `stroke="black" fill="black"`,
+	"bar-title":             `font-family="Arial,Helvetica,sans-serif"`,
+	"lane":                  `stroke="#d3d3d3" fill="#ffffff"`,
+	"lane-title":            `font-family="Arial,Helvetica,sans-serif" font-weight="bold"`,
+	"lane-line":             `stroke="#d3d3d3"`,
+}
+
+// SetClassAttributes sets the attributes of a class, overriding
+// DefaultClassAttributes, e.g. fill="url(#sky)" referring to a
+// gradient added with SVG.Define.
+func (s *Style) SetClassAttributes(class, attributes string) {
+	if s.styles == nil {
+		s.styles = make(map[string]string)
+	}
+	s.styles[class] = attributes
 }
 
 // Write adds a style attribute based on class. Limited to 1 class
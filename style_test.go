@@ -70,6 +70,15 @@ func TestStyle_Write_adds_style_to_classed_elements(t *testing.T) {
 	}
 }
 
+func TestStyle_SetClassAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewStyle(&buf)
+	s.SetClassAttributes("line", `fill="url(#sky)"`)
+	s.Write([]byte(`<x class="line" />`))
+	assert := asserter.New(t)
+	assert().Equals(buf.String(), `<x fill="url(#sky)" />`)
+}
+
 func TestStyle_write(t *testing.T) {
 	s := &Style{
 		err: fmt.Errorf("wrong"),
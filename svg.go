@@ -30,6 +30,8 @@ func NewSVG() *SVG {
 type SVG struct {
 	width, height int
 	Content       []SVGWriter
+
+	defs []Def
 }
 
 func (s *SVG) WriteSVG(out io.Writer) error {
@@ -39,6 +41,14 @@ func (s *SVG) WriteSVG(out io.Writer) error {
   xmlns:xlink="http://www.w3.org/1999/xlink"
   class="root" width="%v" height="%v">`, s.width, s.height)
 
+	if defs := s.Defs(); len(defs) > 0 {
+		w.Print("\n<defs>")
+		for _, d := range defs {
+			w.Print("\n")
+			d.WriteSVG(w)
+		}
+		w.Print("\n</defs>")
+	}
 	for _, c := range s.Content {
 		w.Print("\n")
 		c.WriteSVG(w)
@@ -3,6 +3,7 @@ package draw
 import (
 	"bytes"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/gregoryv/asserter"
@@ -63,3 +64,32 @@ func (d *dummy) WriteSVG(w io.Writer) error {
 	_, err := w.Write([]byte("..."))
 	return err
 }
+
+func TestSVG_Define(t *testing.T) {
+	s := NewSVG()
+	sky := NewLinearGradient("sky", "#ffffff", "#99e6ff")
+	s.Define(sky, NewHatch("out", "#d3d3d3"), sky)
+	s.Append(&dummy{})
+	w := bytes.NewBufferString("")
+	s.WriteSVG(w)
+	got := w.String()
+	assert := asserter.New(t)
+	assert().Equals(strings.Count(got, `<linearGradient id="sky">`), 1)
+	assert().Contains(got, `<stop offset="1" stop-color="#99e6ff"/>`)
+	assert().Contains(got, `<pattern id="out"`)
+	assert().Equals(URL(sky), "url(#sky)")
+}
+
+func TestSVG_Defs_of_content(t *testing.T) {
+	s := NewSVG()
+	glow := NewRadialGradient("glow", "yellow", "white")
+	s.Define(glow)
+	s.Append(&withDefs{glow}, &withDefs{NewHatch("h", "red")})
+	assert := asserter.New(t)
+	assert().Equals(len(s.Defs()), 2)
+}
+
+type withDefs struct{ def Def }
+
+func (d *withDefs) WriteSVG(w io.Writer) error { return nil }
+func (d *withDefs) Defs() []Def                { return []Def{d.def} }
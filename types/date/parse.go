@@ -0,0 +1,100 @@
+package date
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse returns the time of v given as
+//
+//	yyyymmdd             20191111
+//	ISO 8601 date        2019-11-11, 2019-11-11T08:30:00Z
+//	ISO 8601 week date   2019-W46-1, 2019-W46 (Monday)
+//	relative to ref      +3d, -2w, +0d
+//
+// Times without zone are in UTC.
+func Parse(v string, ref time.Time) (time.Time, error) {
+	switch {
+	case strings.HasPrefix(v, "+") || strings.HasPrefix(v, "-"):
+		return parseRelative(v, ref)
+	case strings.Contains(v, "W"):
+		return parseWeek(v)
+	}
+	for _, layout := range layouts {
+		if len(v) != len(layout) && layout != time.RFC3339 {
+			continue
+		}
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("bad date %q", v)
+}
+
+var layouts = []string{
+	"20060102",
+	"2006-01-02",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"20060102T150405Z",
+	time.RFC3339,
+}
+
+// parseRelative parses e.g. +3d or -2w as days or weeks from ref.
+func parseRelative(v string, ref time.Time) (time.Time, error) {
+	if len(v) < 3 {
+		return time.Time{}, fmt.Errorf("bad relative date %q", v)
+	}
+	n, err := strconv.Atoi(v[:len(v)-1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("bad relative date %q", v)
+	}
+	switch v[len(v)-1] {
+	case 'd':
+		return ref.AddDate(0, 0, n), nil
+	case 'w':
+		return ref.AddDate(0, 0, 7*n), nil
+	}
+	return time.Time{}, fmt.Errorf("bad relative date %q, expected unit d or w", v)
+}
+
+// parseWeek parses ISO 8601 week dates, yyyy-Www-d where the day is
+// optional and 1 is Monday.
+func parseWeek(v string) (time.Time, error) {
+	bad := fmt.Errorf("bad week date %q", v)
+	parts := strings.Split(v, "-")
+	if len(parts) < 2 || len(parts) > 3 || len(parts[0]) != 4 ||
+		len(parts[1]) != 3 || parts[1][0] != 'W' {
+		return time.Time{}, bad
+	}
+	year, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return time.Time{}, bad
+	}
+	week, err := strconv.Atoi(parts[1][1:])
+	if err != nil || week < 1 || week > 53 {
+		return time.Time{}, bad
+	}
+	day := 1
+	if len(parts) == 3 {
+		day, err = strconv.Atoi(parts[2])
+		if err != nil || day < 1 || day > 7 {
+			return time.Time{}, bad
+		}
+	}
+	// week 1 is the week with January 4th
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	t := monday.AddDate(0, 0, (week-1)*7+day-1)
+	if _, w := t.ISOWeek(); w != week {
+		return time.Time{}, bad // week 53 in a year with 52 weeks
+	}
+	return t, nil
+}
+
+// Parse returns the time of s, see package func Parse.
+func (s String) Parse(ref time.Time) (time.Time, error) {
+	return Parse(string(s), ref)
+}
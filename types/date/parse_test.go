@@ -0,0 +1,55 @@
+package date
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	ref := time.Date(2019, 11, 11, 0, 0, 0, 0, time.UTC)
+	ok := func(v string, exp time.Time) {
+		t.Helper()
+		got, err := Parse(v, ref)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !got.Equal(exp) {
+			t.Errorf("%s: got %v, expected %v", v, got, exp)
+		}
+	}
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	ok("20191101", day(2019, 11, 1))
+	ok("2019-11-01", day(2019, 11, 1))
+	ok("2019-11-01T08:30", day(2019, 11, 1).Add(510*time.Minute))
+	ok("2019-11-01T08:30:00Z", day(2019, 11, 1).Add(510*time.Minute))
+	ok("20191101T083000Z", day(2019, 11, 1).Add(510*time.Minute))
+	ok("2019-W46-1", day(2019, 11, 11))
+	ok("2019-W46", day(2019, 11, 11))
+	ok("2020-W01-3", day(2020, 1, 1))
+	ok("2015-W53-7", day(2016, 1, 3))
+	ok("+3d", day(2019, 11, 14))
+	ok("-2w", day(2019, 10, 28))
+	ok("+0d", ref)
+}
+
+func TestParse_errors(t *testing.T) {
+	for _, v := range []string{
+		"", "hello", "20191199", "2019-13-01", "2019-W54-1", "2019-W53",
+		"2019-W46-8", "19-W46", "+3", "+3y", "+xd",
+	} {
+		if _, err := Parse(v, time.Now()); err == nil {
+			t.Errorf("%q: expected error", v)
+		}
+	}
+}
+
+func TestString_Parse(t *testing.T) {
+	var v String = "20191101"
+	got, err := v.Parse(time.Now())
+	if err != nil || !got.Equal(v.Time()) {
+		t.Error(got, err)
+	}
+}
@@ -8,6 +8,8 @@ import (
 // String has the format of yyyymmdd
 type String string
 
+// Time returns the time of yyyymmdd formatted s and panics on any
+// other format. Use Parse for other formats.
 func (s String) Time() time.Time {
 	var (
 		year  string